@@ -227,13 +227,7 @@ func writeReport(cmd *cobra.Command, report dyff.Report) error {
 
 	// If configured, make sure `dyff` exists with an exit status
 	if reportOptions.exitWithCode {
-		switch len(report.Diffs) {
-		case 0:
-			return ExitCode{Value: 0}
-
-		default:
-			return ExitCode{Value: 1}
-		}
+		return ExitCode{Value: report.ExitCode(1)}
 	}
 
 	return nil
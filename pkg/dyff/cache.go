@@ -0,0 +1,142 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// ReportCache is a size bound cache for reports, which evicts the least
+// recently used report when it is full. It is safe for concurrent use. The
+// cached reports are shared, so they must not be modified.
+type ReportCache struct {
+	mutex   sync.RWMutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type reportCacheEntry struct {
+	key    string
+	report Report
+}
+
+// NewReportCache returns a new report cache, which holds up to size reports
+func NewReportCache(size int) *ReportCache {
+	return &ReportCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get returns the report stored with the given key and marks it as recently
+// used, the boolean result is false if there is no such report
+func (cache *ReportCache) Get(key string) (Report, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return Report{}, false
+	}
+
+	cache.order.MoveToFront(element)
+	return element.Value.(*reportCacheEntry).report, true
+}
+
+// Put stores the report with the given key, where the least recently used
+// report is evicted if the cache is full
+func (cache *ReportCache) Put(key string, report Report) {
+	if cache.size <= 0 {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		element.Value.(*reportCacheEntry).report = report
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&reportCacheEntry{key: key, report: report})
+
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*reportCacheEntry).key)
+	}
+}
+
+// Len returns the number of reports in the cache
+func (cache *ReportCache) Len() int {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	return cache.order.Len()
+}
+
+// CompareCached compares the input data like CompareBytes, but returns the
+// report from the cache in case the same input data was already compared
+// using the same compare options. Comparisons using options with functions
+// (PreprocessFunc and RegisterComparator) are not cached, since functions
+// cannot be told apart reliably.
+func CompareCached(from []byte, to []byte, cache *ReportCache, compareOptions ...CompareOption) (Report, error) {
+	key, ok := reportCacheKey(from, to, newCompare(compareOptions...).settings)
+	if !ok || cache == nil {
+		return CompareBytes(from, to, compareOptions...)
+	}
+
+	if report, found := cache.Get(key); found {
+		return report, nil
+	}
+
+	report, err := CompareBytes(from, to, compareOptions...)
+	if err != nil {
+		return Report{}, err
+	}
+
+	cache.Put(key, report)
+	return report, nil
+}
+
+// reportCacheKey returns a hash of the input data and the compare settings,
+// the boolean result is false if the settings contain functions
+func reportCacheKey(from []byte, to []byte, settings compareSettings) (string, bool) {
+	if settings.PreprocessFunc != nil || len(settings.Comparators) > 0 {
+		return "", false
+	}
+
+	hash := sha256.New()
+	for _, input := range [][]byte{from, to} {
+		_, _ = fmt.Fprintf(hash, "%d:", len(input))
+		_, _ = hash.Write(input)
+	}
+
+	_, _ = fmt.Fprintf(hash, "%v", settings)
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
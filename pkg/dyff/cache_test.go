@@ -0,0 +1,110 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("report cache", func() {
+	from, to := []byte("foo: bar\nlist: [a, b]\n"), []byte("foo: baz\nlist: [b, a]\n")
+
+	Context("comparing with a cache", func() {
+		It("should return the cached report for the same input and options", func() {
+			cache := dyff.NewReportCache(10)
+
+			first, err := dyff.CompareCached(from, to, cache)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.Diffs).To(HaveLen(2))
+			Expect(cache.Len()).To(Equal(1))
+
+			second, err := dyff.CompareCached(from, to, cache)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(&second.Diffs[0]).To(BeIdenticalTo(&first.Diffs[0]))
+			Expect(cache.Len()).To(Equal(1))
+		})
+
+		It("should compare again for different input or options", func() {
+			cache := dyff.NewReportCache(10)
+
+			_, err := dyff.CompareCached(from, to, cache)
+			Expect(err).ToNot(HaveOccurred())
+
+			report, err := dyff.CompareCached(from, to, cache, dyff.IgnoreOrderChanges(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).To(HaveLen(1))
+
+			report, err = dyff.CompareCached(from, from, cache)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).To(BeEmpty())
+
+			Expect(cache.Len()).To(Equal(3))
+		})
+
+		It("should evict the least recently used report", func() {
+			cache := dyff.NewReportCache(2)
+			cache.Put("a", dyff.Report{})
+			cache.Put("b", dyff.Report{})
+
+			_, found := cache.Get("a")
+			Expect(found).To(BeTrue())
+
+			cache.Put("c", dyff.Report{})
+			Expect(cache.Len()).To(Equal(2))
+
+			_, found = cache.Get("b")
+			Expect(found).To(BeFalse())
+
+			_, found = cache.Get("a")
+			Expect(found).To(BeTrue())
+
+			_, found = cache.Get("c")
+			Expect(found).To(BeTrue())
+		})
+
+		It("should be safe for concurrent use", func() {
+			cache := dyff.NewReportCache(5)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					to := []byte(fmt.Sprintf("foo: %d\n", i%8))
+					report, err := dyff.CompareCached(from, to, cache)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(report.Diffs).ToNot(BeEmpty())
+				}(i)
+			}
+
+			wg.Wait()
+			Expect(cache.Len()).To(Equal(5))
+		})
+	})
+})
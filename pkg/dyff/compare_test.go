@@ -21,14 +21,27 @@
 package dyff_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sort"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
 
 	"github.com/homeport/dyff/pkg/dyff"
 )
 
+type unmarshallable struct{}
+
+func (unmarshallable) MarshalYAML() (interface{}, error) {
+	return nil, fmt.Errorf("cannot be marshalled")
+}
+
 var _ = Describe("Core/Compare", func() {
 	Describe("Difference between YAMLs", func() {
 		Context("Given two simple YAML structures", func() {
@@ -781,6 +794,61 @@ listY: [ Yo, Yo, Yo ]
 
 				Expect(report.ExcludeRegexp("/does/not/exist")).To(BeEquivalentTo(report))
 			})
+			It("should match differences without a path as an empty string using regular expressions", func() {
+				documentRemoval := dyff.Diff{
+					Path: nil,
+					Details: []dyff.Detail{{
+						Kind: dyff.REMOVAL,
+						From: &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{yml(`{foo: bar}`)}},
+					}},
+				}
+
+				report := dyff.Report{Diffs: []dyff.Diff{
+					documentRemoval,
+					singleDiff("/yaml/map/foobar", dyff.ADDITION, nil, "foobar"),
+				}}
+
+				Expect(report.FilterRegexpNilAsEmpty("^$")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					documentRemoval,
+				}}))
+
+				Expect(report.ExcludeRegexpNilAsEmpty("^$")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/yaml/map/foobar", dyff.ADDITION, nil, "foobar"),
+				}}))
+
+				Expect(report.FilterRegexpNilAsEmpty("foobar")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/yaml/map/foobar", dyff.ADDITION, nil, "foobar"),
+				}}))
+
+				Expect(report.ExcludeRegexpNilAsEmpty("foobar")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					documentRemoval,
+				}}))
+			})
+
+			It("should never match differences without a path using regular expressions by default", func() {
+				documentRemoval := dyff.Diff{
+					Path: nil,
+					Details: []dyff.Detail{{
+						Kind: dyff.REMOVAL,
+						From: &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{yml(`{foo: bar}`)}},
+					}},
+				}
+
+				report := dyff.Report{Diffs: []dyff.Diff{
+					documentRemoval,
+					singleDiff("/yaml/map/foobar", dyff.ADDITION, nil, "foobar"),
+				}}
+
+				Expect(report.FilterRegexp("^$").Diffs).To(BeEmpty())
+				Expect(report.FilterRegexp(".*")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/yaml/map/foobar", dyff.ADDITION, nil, "foobar"),
+				}}))
+
+				Expect(report.ExcludeRegexp("^$")).To(BeEquivalentTo(report))
+				Expect(report.ExcludeRegexp(".*")).To(BeEquivalentTo(dyff.Report{Diffs: []dyff.Diff{
+					documentRemoval,
+				}}))
+			})
 		})
 
 		Context("change root for comparison", func() {
@@ -1044,5 +1112,1717 @@ b: bar
 				Expect(len(results.Diffs)).To(Equal(0))
 			})
 		})
+
+		Context("comparing raw input data in different formats", func() {
+			It("should compare two dotenv inputs", func() {
+				from := []byte(`# database settings
+DB_HOST=localhost
+DB_PORT=5432
+
+export DB_USER="admin"
+`)
+
+				to := []byte(`# database settings
+DB_HOST=db.example.org
+
+DB_USER='admin'
+DB_NAME=app
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatDotEnv))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(2))
+
+				Expect(report.Diffs[0].Path.String()).To(Equal("/"))
+				Expect(report.Diffs[0].Details).To(HaveLen(2))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(report.Diffs[0].Details[0].From.Content[0].Value).To(Equal("DB_PORT"))
+				Expect(report.Diffs[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[1].To.Content[0].Value).To(Equal("DB_NAME"))
+
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/DB_HOST", dyff.MODIFICATION, "localhost", "db.example.org")))
+			})
+
+			It("should compare two properties inputs", func() {
+				from := []byte(`! application settings
+app.name = foobar
+app.port: 8080
+`)
+
+				to := []byte(`# application settings
+app.name = barfoo
+app.port: 8080
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatProperties))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path.PathElements[0].Name).To(Equal("app.name"))
+				Expect(report.Diffs[0].Details[0].From.Value).To(Equal("foobar"))
+				Expect(report.Diffs[0].Details[0].To.Value).To(Equal("barfoo"))
+			})
+
+			It("should compare two INI inputs", func() {
+				from := []byte(`; global settings
+log_level = info
+
+[database]
+host = localhost
+port = 5432
+`)
+
+				to := []byte(`# global settings
+log_level = info
+
+[database]
+host = "db.example.org"
+port = 5432
+
+[cache]
+ttl: 60
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatINI))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(2))
+
+				Expect(report.Diffs[0].Path.String()).To(Equal("/"))
+				Expect(report.Diffs[0].Details).To(HaveLen(1))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[0].To.Content[0].Value).To(Equal("cache"))
+
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/database/host", dyff.MODIFICATION, "localhost", "db.example.org")))
+			})
+
+			It("should put INI keys outside of any section into the default section", func() {
+				report, err := dyff.CompareBytes([]byte("foo = bar\n"), []byte("foo = baz\n"), dyff.Format(dyff.FormatINI))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/default/foo", dyff.MODIFICATION, "bar", "baz")))
+			})
+
+			It("should compare two TOML inputs", func() {
+				from := []byte(`title = "example"
+port = 8080
+
+[database]
+host = "localhost"
+user = "admin"
+
+[[servers]]
+name = "alpha"
+`)
+
+				to := []byte(`title = "example"
+debug = true
+
+[database]
+host = "db.example.org"
+user = "admin"
+options = { timeout = 30 }
+
+[[servers]]
+name = "alpha"
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatTOML))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(3))
+
+				Expect(report.Diffs[0].Path.String()).To(Equal("/"))
+				Expect(report.Diffs[0].Details).To(HaveLen(2))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(report.Diffs[0].Details[0].From.Content[0].Value).To(Equal("port"))
+				Expect(report.Diffs[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[1].To.Content[0].Value).To(Equal("debug"))
+
+				Expect(report.Diffs[1].Path.String()).To(Equal("/database"))
+				Expect(report.Diffs[1].Details).To(HaveLen(1))
+				Expect(report.Diffs[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[1].Details[0].To.Content[0].Value).To(Equal("options"))
+				Expect(report.Diffs[1].Details[0].To.Content[1].Kind).To(Equal(yamlv3.MappingNode))
+
+				Expect(report.Diffs[2]).To(BeSameDiffAs(singleDiff("/database/host", dyff.MODIFICATION, "localhost", "db.example.org")))
+			})
+
+			It("should compare two HCL inputs with an added attribute", func() {
+				from := []byte(`# network settings
+region = "eu-west-1"
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+  tags          = { Name = "web" }
+}
+`)
+
+				to := []byte(`# network settings
+region = "eu-west-1"
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+  tags          = { Name = "web" }
+  monitoring    = true
+}
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatHCL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path.String()).To(Equal("/resource/aws_instance/web"))
+				Expect(report.Diffs[0].Details).To(HaveLen(1))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[0].To.Content[0].Value).To(Equal("monitoring"))
+				Expect(report.Diffs[0].Details[0].To.Content[1].Tag).To(Equal("!!bool"))
+			})
+
+			It("should compare two HCL inputs with a changed block label", func() {
+				from := []byte(`resource "aws_instance" "web" {
+  ami   = "ami-123"
+  ports = [80, 443]
+}
+`)
+
+				to := []byte(`resource "aws_instance" "api" {
+  ami   = "ami-123"
+  ports = [80, 443]
+}
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatHCL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path.String()).To(Equal("/resource/aws_instance"))
+				Expect(report.Diffs[0].Details).To(HaveLen(2))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(report.Diffs[0].Details[0].From.Content[0].Value).To(Equal("web"))
+				Expect(report.Diffs[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[1].To.Content[0].Value).To(Equal("api"))
+			})
+
+			It("should compare HCL inputs with multi-argument and multi-line function calls", func() {
+				from := []byte(`locals {
+  name = join("-", [var.prefix, "web"])
+  tags = merge(
+    var.tags,
+    { Name = "web-${var.env}" }
+  )
+}
+`)
+
+				to := []byte(`locals {
+  name = join("-", [var.prefix, "api"])
+  tags = merge(
+    var.tags,
+    { Name = "web-${var.env}" }
+  )
+}
+`)
+
+				report, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatHCL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/locals/name",
+					dyff.MODIFICATION,
+					`join("-", [var.prefix, "web"])`,
+					`join("-", [var.prefix, "api"])`,
+				)))
+
+				tags, err := ytbx.Grab(report.From.Documents[0], "/locals/tags")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tags.Value).To(Equal("merge(\n    var.tags,\n    { Name = \"web-${var.env}\" }\n  )"))
+			})
+
+			It("should keep template interpolations in HCL strings as-is", func() {
+				report, err := dyff.CompareBytes(
+					[]byte(`name = "web-${var.env}"`+"\n"),
+					[]byte(`name = "api-${var.env}"`+"\n"),
+					dyff.Format(dyff.FormatHCL),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/name", dyff.MODIFICATION, "web-${var.env}", "api-${var.env}")))
+			})
+
+			It("should fail for malformed HCL input", func() {
+				_, err := dyff.CompareBytes([]byte(`x = merge(a,`), []byte(`x = 1`), dyff.Format(dyff.FormatHCL))
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail for malformed dotenv input", func() {
+				_, err := dyff.CompareBytes([]byte("FOO"), []byte("FOO=bar"), dyff.Format(dyff.FormatDotEnv))
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should compare gzip compressed input the same way as uncompressed input", func() {
+				from := []byte("---\nfoo: bar\nlist:\n- one\n- two\n")
+				to := []byte("---\nfoo: BAR\nlist:\n- one\n- three\n")
+
+				gzipped := func(input []byte) []byte {
+					var buf bytes.Buffer
+					writer := gzip.NewWriter(&buf)
+					_, err := writer.Write(input)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(writer.Close()).To(Succeed())
+					return buf.Bytes()
+				}
+
+				expected, err := dyff.CompareBytes(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(expected.Diffs).To(HaveLen(2))
+
+				actual, err := dyff.CompareBytes(gzipped(from), gzipped(to))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual.Diffs).To(HaveLen(len(expected.Diffs)))
+				for i := range expected.Diffs {
+					Expect(actual.Diffs[i]).To(BeSameDiffAs(expected.Diffs[i]))
+				}
+
+				mixed, err := dyff.CompareBytes(gzipped(from), to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mixed.Diffs).To(HaveLen(len(expected.Diffs)))
+			})
+
+			It("should detect the input format automatically by default", func() {
+				report, err := dyff.CompareBytes([]byte(`{"foo": "bar"}`), []byte("foo: BAR"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/foo", dyff.MODIFICATION, "bar", "BAR")))
+			})
+		})
+
+		Context("comparing selected documents only", func() {
+			from := ytbx.InputFile{Documents: multiDoc("foo: bar", "bar: foo", "foobar: barfoo")}
+			to := ytbx.InputFile{Documents: multiDoc("foo: BAR", "bar: foo", "foobar: BARFOO")}
+
+			It("should only compare the selected documents", func() {
+				report, err := dyff.CompareInputFiles(from, to, dyff.DocumentSelector(1, 2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#2/foobar", dyff.MODIFICATION, "barfoo", "BARFOO")))
+			})
+
+			It("should report the differences with the original document index", func() {
+				report, err := dyff.CompareInputFiles(from, to, dyff.DocumentSelector(2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path.DocumentIdx).To(Equal(2))
+
+				var buf bytes.Buffer
+				Expect((&dyff.HumanReport{Report: report, OmitHeader: true}).WriteReport(&buf)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring("foobar  (document #3)"))
+			})
+
+			It("should report the original document index of Kubernetes documents", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: one}, data: {key: foo}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: two}, data: {key: foo}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: three}, data: {key: foo}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: one}, data: {key: bar}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: two}, data: {key: foo}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: three}, data: {key: bar}}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true), dyff.DocumentSelector(2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#2/data/key", dyff.MODIFICATION, "foo", "bar")))
+				Expect(report.Diffs[0].Path.DocumentIdx).To(Equal(2))
+				Expect(report.Diffs[0].Path.RootDescription()).To(Equal("ConfigMap/default/three"))
+			})
+
+			It("should fail if a selected document index is out of range", func() {
+				_, err := dyff.CompareInputFiles(from, to, dyff.DocumentSelector(0, 3))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("document index 3 is out of range"))
+			})
+		})
+
+		Context("comparing explicit null values with missing entries", func() {
+			It("should not report a null value that was added if configured", func() {
+				result, err := compare(yml(`{foo: bar}`), yml(`{foo: bar, bar: null}`), dyff.NullEqualsMissing(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not report a null value that was removed if configured", func() {
+				result, err := compare(yml(`{foo: bar, bar: ~}`), yml(`{foo: bar}`), dyff.NullEqualsMissing(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report other additions and removals", func() {
+				result, err := compare(yml(`{foo: bar, bar: null}`), yml(`{bar: null, baz: null, foobar: barfoo}`), dyff.NullEqualsMissing(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[0].Details[0].From.Content[0].Value).To(Equal("foo"))
+				Expect(result[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(result[0].Details[1].To.Content).To(HaveLen(2))
+				Expect(result[0].Details[1].To.Content[0].Value).To(Equal("foobar"))
+			})
+
+			It("should report null values that were added by default", func() {
+				result, err := compare(yml(`{foo: bar}`), yml(`{foo: bar, bar: null}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+		})
+
+		Context("comparing YAMLs that only differ in style or comments", func() {
+			It("should not report differences in the quoting style of strings", func() {
+				from := yml(`---
+single: 'foobar'
+double: "foobar"
+plain: foobar
+block: |
+  foobar
+`)
+
+				to := yml(`---
+single: "foobar"
+double: foobar
+plain: 'foobar'
+block: "foobar\n"
+`)
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not report differences in comments", func() {
+				from := yml(`---
+# head comment
+foo: bar # line comment
+list:
+- one
+- two
+`)
+
+				to := yml(`---
+foo: bar # another line comment
+list:
+# comment inside the list
+- one
+- two # trailing comment
+`)
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+		})
+
+		Context("comparing numbers represented as strings", func() {
+			It("should consider a quoted number equal to the number if configured", func() {
+				result, err := compare(yml(`{port: "8080", ratio: 0.5}`), yml(`{port: 8080, ratio: "0.5"}`), dyff.CoerceStringNumbers(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report a string that does not parse as a number", func() {
+				result, err := compare(yml(`{port: "8080a"}`), yml(`{port: 8080}`), dyff.CoerceStringNumbers(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/port", dyff.MODIFICATION, "8080a", 8080)))
+			})
+
+			It("should report a quoted number by default", func() {
+				result, err := compare(yml(`{port: "8080"}`), yml(`{port: 8080}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/port", dyff.MODIFICATION, "8080", 8080)))
+			})
+		})
+
+		Context("comparing Kubernetes resources with the Kubernetes defaults", func() {
+			It("should ignore changes in cluster maintained fields, but report spec changes", func() {
+				from := yml(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  generation: 1
+  managedFields:
+  - manager: kubectl
+    operation: Apply
+spec:
+  replicas: 1
+status:
+  readyReplicas: 1
+`)
+
+				to := yml(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  generation: 2
+  managedFields:
+  - manager: kubectl
+    operation: Update
+  - manager: kube-controller-manager
+    operation: Update
+spec:
+  replicas: 3
+`)
+
+				result, err := compare(from, to, dyff.KubernetesDefaults())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 3)))
+			})
+
+			It("should ignore additional paths", func() {
+				result, err := compare(yml(`{foo: {bar: 1}, baz: 1}`), yml(`{foo: {bar: 2}, baz: 2}`), dyff.IgnorePaths("foo.bar"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/baz", dyff.MODIFICATION, 1, 2)))
+			})
+
+			It("should panic for paths that cannot be parsed", func() {
+				Expect(func() { dyff.IgnorePaths("/foo=bar=baz") }).To(PanicWith(ContainSubstring(`IgnorePaths("/foo=bar=baz")`)))
+			})
+		})
+
+		Context("comparing with a limited compare depth", func() {
+			from := yml(`---
+top: foo
+some:
+  deeply:
+    nested:
+      structure:
+        name: foobar
+        version: v1
+`)
+
+			to := yml(`---
+top: bar
+some:
+  deeply:
+    nested:
+      structure:
+        name: barfoo
+        version: v2
+`)
+
+			It("should report a differing subtree below the limit as one modification", func() {
+				result, err := compare(from, to, dyff.MaxCompareDepth(2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/top", dyff.MODIFICATION, "foo", "bar")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/some/deeply", dyff.MODIFICATION,
+					yml(`{nested: {structure: {name: foobar, version: v1}}}`),
+					yml(`{nested: {structure: {name: barfoo, version: v2}}}`),
+				)))
+			})
+
+			It("should not report a subtree below the limit without differences", func() {
+				result, err := compare(from, from, dyff.MaxCompareDepth(1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should report all leaves individually by default", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+			})
+		})
+
+		Context("comparing with custom comparators", func() {
+			semver := func(from, to *yamlv3.Node) bool {
+				normalize := func(version string) string {
+					for strings.HasSuffix(version, ".0") {
+						version = strings.TrimSuffix(version, ".0")
+					}
+
+					return version
+				}
+
+				return normalize(from.Value) == normalize(to.Value)
+			}
+
+			never := func(from, to *yamlv3.Node) bool { return false }
+
+			It("should use the custom comparator for matching paths", func() {
+				result, err := compare(
+					yml(`{spec: {version: "1.2.0", other: "1.2.0"}}`),
+					yml(`{spec: {version: "1.2", other: "1.2"}}`),
+					dyff.RegisterComparator("/spec/version", semver),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/other", dyff.MODIFICATION, "1.2.0", "1.2")))
+			})
+
+			It("should use the most specific comparator if more than one matches", func() {
+				result, err := compare(
+					yml(`{spec: {version: "1.2.0", other: "1.2.0"}}`),
+					yml(`{spec: {version: "1.2", other: "1.2"}}`),
+					dyff.RegisterComparator("/spec/*", never),
+					dyff.RegisterComparator("/spec/version", semver),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/other", dyff.MODIFICATION, "1.2.0", "1.2")))
+			})
+		})
+
+		Context("comparing only a subtree", func() {
+			It("should only report differences within the selected subtree with relative paths", func() {
+				result, err := compare(
+					yml(`{metadata: {name: foo}, spec: {template: {image: "foo:1", replicas: 1}}}`),
+					yml(`{metadata: {name: bar}, spec: {template: {image: "foo:2", replicas: 1}}}`),
+					dyff.RootPath("/spec/template"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/image", dyff.MODIFICATION, "foo:1", "foo:2")))
+			})
+
+			It("should report the whole subtree if it is missing on one side", func() {
+				result, err := compare(
+					yml(`{metadata: {name: foo}}`),
+					yml(`{metadata: {name: foo}, spec: {template: {image: "foo:1"}}}`),
+					dyff.RootPath("/spec/template"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.PathElements).To(BeEmpty())
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result[0].Details[0].From).To(BeNil())
+				Expect(result[0].Details[0].To.Kind).To(Equal(yamlv3.MappingNode))
+				Expect(result[0].Details[0].To.Content[1].Value).To(Equal("foo:1"))
+			})
+		})
+
+		Context("comparing with empty collections being equal to absent entries", func() {
+			It("should not report an empty map that is missing on the other side", func() {
+				result, err := compare(yml(`{foo: bar, annotations: {}}`), yml(`{foo: bar}`), dyff.EmptyCollectionsEqualAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not report an empty list that is missing on the other side", func() {
+				result, err := compare(yml(`{foo: bar}`), yml(`{foo: bar, items: []}`), dyff.EmptyCollectionsEqualAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report non-empty collections that are missing on the other side", func() {
+				result, err := compare(yml(`{foo: bar}`), yml(`{foo: bar, items: [foobar]}`), dyff.EmptyCollectionsEqualAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result[0].Details[0].To.Content[0].Value).To(Equal("items"))
+			})
+		})
+
+		Context("comparing numbers by their canonical value", func() {
+			It("should not report numbers in scientific notation with the same value", func() {
+				result, err := compare(yml(`{replicas: 1e3, ratio: 0.5}`), yml(`{replicas: 1000, ratio: 5e-1}`), dyff.CanonicalizeNumbers(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not report hexadecimal and decimal integers with the same value", func() {
+				result, err := compare(yml(`{port: 0x10}`), yml(`{port: 16}`), dyff.CanonicalizeNumbers(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report numbers with different values or by default", func() {
+				result, err := compare(yml(`{replicas: 1e3}`), yml(`{replicas: 1001}`), dyff.CanonicalizeNumbers(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				result, err = compare(yml(`{replicas: 1e3}`), yml(`{replicas: 1000}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+		})
+
+		Context("comparing lists using configured merge keys", func() {
+			from := yml(`{spec: {ports: [{name: http, port: 80}, {name: https, port: 443}]}}`)
+			to := yml(`{spec: {ports: [{name: web, port: 80}, {name: https, port: 443}]}}`)
+
+			It("should identify list entries by the configured merge key", func() {
+				result, err := compare(from, to, dyff.MergeKeys(map[string]string{"/spec/ports": "port"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/ports/port=80/name", dyff.MODIFICATION, "http", "web")))
+			})
+
+			It("should use the default identifier detection for other paths", func() {
+				result, err := compare(from, to, dyff.MergeKeys(map[string]string{"/spec/other": "port"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.String()).To(Equal("/spec/ports"))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+
+			It("should use the most specific matching pattern", func() {
+				for i := 0; i < 10; i++ {
+					result, err := compare(from, to, dyff.MergeKeys(map[string]string{
+						"/*/ports":    "name",
+						"/spec/ports": "port",
+						"/spec/*":     "name",
+					}))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(HaveLen(1))
+					Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/ports/port=80/name", dyff.MODIFICATION, "http", "web")))
+				}
+			})
+		})
+
+		Context("comparing only keys", func() {
+			It("should not report value or order changes", func() {
+				result, err := compare(
+					yml(`{name: foo, replicas: 1, list: [a, b], map: {key: value}}`),
+					yml(`{name: bar, replicas: [1], list: [b, a, c], map: {key: other}}`),
+					dyff.KeysOnly(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report added and removed keys", func() {
+				result, err := compare(
+					yml(`{name: foo, map: {key: value, old: value}}`),
+					yml(`{name: bar, map: {key: value, new: value}}`),
+					dyff.KeysOnly(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.String()).To(Equal("/map"))
+				Expect(result[0].Details).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[0].Details[0].From.Content[0].Value).To(Equal("old"))
+				Expect(result[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(result[0].Details[1].To.Content[0].Value).To(Equal("new"))
+			})
+		})
+
+		Context("comparing only specific Kubernetes resource kinds", func() {
+			It("should only report differences in documents of the allowed kinds", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 1}}",
+					"{apiVersion: v1, kind: Service, metadata: {name: foo}, spec: {port: 80}}",
+					"{apiVersion: apps/v1, kind: StatefulSet, metadata: {name: bar}, spec: {image: 'bar:1'}}",
+					"{foo: bar}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 2}}",
+					"{apiVersion: v1, kind: Service, metadata: {name: foo}, spec: {port: 8080}}",
+					"{apiVersion: apps/v1, kind: StatefulSet, metadata: {name: bar}, spec: {image: 'bar:2'}}",
+					"{foo: baz}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KindAllowlist("Deployment", "StatefulSet"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(2))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#0/spec/replicas", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("#2/spec/image", dyff.MODIFICATION, "bar:1", "bar:2")))
+			})
+
+			It("should filter added and removed documents by their kind", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: foo}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: bar}}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true), dyff.KindAllowlist("Deployment"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path).To(BeNil())
+				Expect(report.Diffs[0].Details).To(HaveLen(2))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(report.Diffs[0].Details[0].From.Content).To(HaveLen(1))
+				Expect(report.Diffs[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[0].Details[1].To.Content).To(HaveLen(1))
+			})
+
+			It("should check the kind of the from document if the documents were reordered", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 1}}",
+					"{apiVersion: v1, kind: Service, metadata: {name: foo}, spec: {port: 80}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: v1, kind: Service, metadata: {name: foo}, spec: {port: 8080}}",
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 2}}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true), dyff.KindAllowlist("Service"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#1/spec/port", dyff.MODIFICATION, 80, 8080)))
+			})
+		})
+
+		Context("comparing without specific Kubernetes resource kinds", func() {
+			It("should drop differences in documents of the denied kinds", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 1}}",
+					"{apiVersion: v1, kind: Event, metadata: {name: foo}, count: 1}",
+					"{apiVersion: coordination.k8s.io/v1, kind: Lease, metadata: {name: foo}, spec: {renewTime: a}}",
+					"{foo: bar}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 2}}",
+					"{apiVersion: v1, kind: Event, metadata: {name: foo}, count: 2}",
+					"{apiVersion: coordination.k8s.io/v1, kind: Lease, metadata: {name: foo}, spec: {renewTime: b}}",
+					"{foo: baz}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KindDenylist("Event", "Lease"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(2))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#0/spec/replicas", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("#3/foo", dyff.MODIFICATION, "bar", "baz")))
+			})
+
+			It("should drop added and removed documents of the denied kinds", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}}",
+					"{apiVersion: v1, kind: Event, metadata: {name: foo}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}}",
+					"{apiVersion: v1, kind: Event, metadata: {name: bar}}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true), dyff.KindDenylist("Event"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(BeEmpty())
+			})
+
+			It("should drop differences in denied documents if the documents were reordered", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 1}}",
+					"{apiVersion: coordination.k8s.io/v1, kind: Lease, metadata: {name: foo}, spec: {renewTime: a}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: coordination.k8s.io/v1, kind: Lease, metadata: {name: foo}, spec: {renewTime: b}}",
+					"{apiVersion: apps/v1, kind: Deployment, metadata: {name: foo}, spec: {replicas: 2}}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true), dyff.KindDenylist("Lease"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#0/spec/replicas", dyff.MODIFICATION, 1, 2)))
+			})
+		})
+
+		Context("comparing lists as multisets", func() {
+			It("should report surplus occurrences as removals and additions", func() {
+				result, err := compare(
+					yml(`{tolerations: [{key: foo}, {key: bar}, {key: foo}, {key: baz}]}`),
+					yml(`{tolerations: [{key: bar}, {key: foo}, {key: baz}, {key: baz}]}`),
+					dyff.MultisetPaths("/tolerations"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(doubleDiff("/tolerations",
+					dyff.REMOVAL, yml(`[{key: foo}]`), nil,
+					dyff.ADDITION, nil, yml(`[{key: baz}]`),
+				)))
+			})
+
+			It("should not report order changes or single entry modifications", func() {
+				result, err := compare(
+					yml(`{list: [a, b, a]}`),
+					yml(`{list: [a, a, b]}`),
+					dyff.MultisetPaths("list"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+
+				result, err = compare(
+					yml(`{list: [a]}`),
+					yml(`{list: [b]}`),
+					dyff.MultisetPaths("list"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+
+			It("should panic for paths that cannot be parsed", func() {
+				Expect(func() { dyff.MultisetPaths("/list=a=b") }).To(PanicWith(ContainSubstring(`MultisetPaths("/list=a=b")`)))
+			})
+		})
+
+		Context("comparing with tag only changes being ignored", func() {
+			It("should not report scalars that only differ by their tag", func() {
+				result, err := compare(yml(`{port: "123", enabled: "true"}`), yml(`{port: 123, enabled: true}`), dyff.IgnoreTagOnlyChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report scalars with different values", func() {
+				result, err := compare(yml(`{port: "123"}`), yml(`{port: 124}`), dyff.IgnoreTagOnlyChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/port", dyff.MODIFICATION, "123", 124)))
+			})
+
+			It("should report tag only changes by default", func() {
+				result, err := compare(yml(`{port: "123"}`), yml(`{port: 123}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+		})
+
+		Context("comparing with suppressed detail kinds", func() {
+			It("should not generate order changes or additions when suppressed", func() {
+				result, err := compare(
+					yml(`{list: [A, C, B], map: {foo: bar}}`),
+					yml(`{list: [A, B, C, D], map: {foo: bar, new: entry}}`),
+					dyff.SuppressKinds(dyff.ORDERCHANGE, dyff.ADDITION),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still generate the kinds that are not suppressed", func() {
+				result, err := compare(
+					yml(`{list: [A, B], name: foo, old: value}`),
+					yml(`{list: [B, A], name: bar}`),
+					dyff.SuppressKinds(dyff.MODIFICATION),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[1].Details).To(HaveLen(1))
+				Expect(result[1].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+			})
+
+			It("should combine the kinds of multiple options", func() {
+				result, err := compare(
+					yml(`{list: [A, C, B], map: {foo: bar}}`),
+					yml(`{list: [A, B, C, D], map: {foo: bar, new: entry}}`),
+					dyff.SuppressKinds(dyff.ORDERCHANGE),
+					dyff.SuppressKinds(dyff.ADDITION),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+		})
+
+		Context("comparing with appends being ignored", func() {
+			It("should not report entries appended to the end of a list", func() {
+				result, err := compare(yml(`{list: [a, b]}`), yml(`{list: [a, b, c, d]}`), dyff.IgnoreAppends(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report entries inserted in the middle of a list", func() {
+				result, err := compare(yml(`{list: [a, b]}`), yml(`{list: [a, x, b]}`), dyff.IgnoreAppends(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should still report modifications of entries in named-entry lists with appends", func() {
+				result, err := compare(
+					yml(`{env: [{name: A, value: "1"}]}`),
+					yml(`{env: [{name: A, value: "2"}, {name: B, value: "3"}]}`),
+					dyff.IgnoreAppends(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/env/name=A/value", dyff.MODIFICATION, "1", "2")))
+			})
+
+			It("should report appends by default", func() {
+				result, err := compare(yml(`{list: [a, b]}`), yml(`{list: [a, b, c]}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+		})
+
+		Context("comparing with a preprocessing function", func() {
+			sortList := func(document *yamlv3.Node) (*yamlv3.Node, error) {
+				list, err := ytbx.Grab(document, "/list")
+				if err != nil {
+					return nil, err
+				}
+
+				sort.Slice(list.Content, func(i, j int) bool {
+					return list.Content[i].Value < list.Content[j].Value
+				})
+
+				return document, nil
+			}
+
+			It("should compare the preprocessed documents", func() {
+				from, to := []byte(`{list: [c, a, b]}`), []byte(`{list: [a, b, c]}`)
+
+				result, err := dyff.CompareBytes(from, to, dyff.Format(dyff.FormatYAML))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Diffs).To(HaveLen(1))
+
+				result, err = dyff.CompareBytes(from, to, dyff.Format(dyff.FormatYAML), dyff.PreprocessFunc(sortList))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Diffs).To(BeEmpty())
+			})
+
+			It("should fail with the side of the failing preprocessing", func() {
+				_, err := dyff.CompareBytes([]byte("list: [a]"), []byte("foo: bar"), dyff.PreprocessFunc(sortList))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("document #1 of to input file"))
+			})
+		})
+
+		Context("comparing documents with duplicate keys", func() {
+			from := []byte("name: foo\nspec:\n  replicas: 1\n  replicas: 2\n")
+			to := []byte("name: bar\nspec:\n  replicas: 2\n")
+
+			It("should add a warning for each duplicate key if configured", func() {
+				report, err := dyff.CompareBytes(from, to, dyff.DetectDuplicateKeys(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).ToNot(BeEmpty())
+				Expect(report.Warnings).To(HaveLen(1))
+				Expect(report.Warnings[0]).To(ContainSubstring(`duplicate key "replicas" in map at /spec in document #1 of from input file`))
+			})
+
+			It("should keep the warnings when filtering the report", func() {
+				report, err := dyff.CompareBytes(from, to, dyff.DetectDuplicateKeys(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Filter("/name").Warnings).To(HaveLen(1))
+			})
+
+			It("should not add warnings by default", func() {
+				report, err := dyff.CompareBytes(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Warnings).To(BeEmpty())
+			})
+		})
+
+		Context("comparing with annotation prefixes being ignored", func() {
+			from := yml(`---
+metadata:
+  annotations:
+    deployment.kubernetes.io/revision: "1"
+    team: a
+spec:
+  template:
+    metadata:
+      annotations:
+        checksum/config: abc
+`)
+
+			to := yml(`---
+metadata:
+  annotations:
+    deployment.kubernetes.io/revision: "2"
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+    team: b
+spec:
+  template:
+    metadata:
+      annotations:
+        checksum/config: def
+`)
+
+			It("should drop changes of annotations with an ignored prefix", func() {
+				result, err := compare(from, to, dyff.IgnoreAnnotationPrefixes("checksum/", "deployment.kubernetes.io/", "kubectl.kubernetes.io/"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/metadata/annotations/team", dyff.MODIFICATION, "a", "b")))
+			})
+
+			It("should report all annotation changes by default", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(4))
+			})
+		})
+
+		Context("comparing with singleton lists being normalized", func() {
+			It("should consider a value and a list with only this value equal", func() {
+				result, err := compare(yml(`{port: 80}`), yml(`{port: [80]}`), dyff.NormalizeSingletonLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+
+				result, err = compare(yml(`{port: [80]}`), yml(`{port: 80}`), dyff.NormalizeSingletonLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report a value and a list with more entries", func() {
+				result, err := compare(yml(`{port: 80}`), yml(`{port: [80, 443]}`), dyff.NormalizeSingletonLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.MODIFICATION))
+			})
+
+			It("should still report a value and a list with a different value", func() {
+				result, err := compare(yml(`{port: 80}`), yml(`{port: [443]}`), dyff.NormalizeSingletonLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+
+			It("should report a value and a singleton list by default", func() {
+				result, err := compare(yml(`{port: 80}`), yml(`{port: [80]}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+		})
+
+		Context("comparing with value patterns being ignored", func() {
+			hash := dyff.IgnoreValuePatterns(`^[0-9a-f]{64}$`)
+
+			It("should not report modifications where both values match a pattern", func() {
+				result, err := compare(
+					yml(`{checksum: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae, name: foo}`),
+					yml(`{checksum: fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9, name: bar}`),
+					hash,
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/name", dyff.MODIFICATION, "foo", "bar")))
+			})
+
+			It("should still report modifications where only one value matches a pattern", func() {
+				result, err := compare(
+					yml(`{checksum: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae}`),
+					yml(`{checksum: none}`),
+					hash,
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+		})
+
+		Context("comparing with multiple compare options combined", func() {
+			from := yml(`---
+metadata:
+  annotations:
+    checksum/config: abc
+spec:
+  replicas: "3"
+  list: [a, b]
+  ports: [80]
+  ignored: foo
+`)
+
+			to := yml(`---
+metadata:
+  annotations:
+    checksum/config: def
+spec:
+  replicas: 3
+  list: [b, a, c]
+  ports: 80
+  ignored: bar
+`)
+
+			It("should apply all options together", func() {
+				result, err := compare(from, to,
+					dyff.IgnorePaths("/spec/ignored"),
+					dyff.IgnoreOrderChanges(true),
+					dyff.CoerceStringNumbers(true),
+					dyff.NormalizeSingletonLists(true),
+					dyff.IgnoreAnnotationPrefixes("checksum/"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.String()).To(Equal("/spec/list"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should report everything with the defaults", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(5))
+			})
+		})
+
+		Context("comparing in scalar only mode", func() {
+			It("should report scalar modifications in detail", func() {
+				result, err := compare(
+					yml(`{spec: {replicas: 1, template: {image: app:1.0}}}`),
+					yml(`{spec: {replicas: 2, template: {image: app:2.0}}}`),
+					dyff.ScalarOnly(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/spec/template/image", dyff.MODIFICATION, "app:1.0", "app:2.0")))
+			})
+
+			It("should summarize a restructured subtree as one removal and addition", func() {
+				result, err := compare(
+					yml(`{spec: {replicas: 1, template: {image: app, tag: "1.0"}}}`),
+					yml(`{spec: {replicas: 1, template: {image: app:1.0, pullPolicy: Always}}}`),
+					dyff.ScalarOnly(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(doubleDiff("/spec/template",
+					dyff.REMOVAL, yml(`{image: app, tag: "1.0"}`), nil,
+					dyff.ADDITION, nil, yml(`{image: app:1.0, pullPolicy: Always}`),
+				)))
+			})
+
+			It("should not consider ignored keys a structural change", func() {
+				result, err := compare(
+					yml(`{spec: {generation: 1, replicas: 1}}`),
+					yml(`{spec: {replicas: 2}}`),
+					dyff.ScalarOnly(true),
+					dyff.IgnorePaths("/spec/generation"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2)))
+			})
+
+			It("should report each change of a restructured subtree by default", func() {
+				result, err := compare(
+					yml(`{spec: {template: {image: app, tag: "1.0"}}}`),
+					yml(`{spec: {template: {image: app:1.0, pullPolicy: Always}}}`),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+			})
+		})
+
+		Context("comparing in subset mode", func() {
+			It("should accept additional keys in to", func() {
+				result, err := compare(
+					yml(`{name: foo, spec: {replicas: 1}}`),
+					yml(`{name: foo, spec: {replicas: 1, paused: false}, status: {ready: true}}`),
+					dyff.SubsetMode(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should report missing and changed keys of from", func() {
+				result, err := compare(
+					yml(`{name: foo, spec: {replicas: 1, paused: true}}`),
+					yml(`{name: bar, spec: {replicas: 1, extra: true}}`),
+					dyff.SubsetMode(true),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/name", dyff.MODIFICATION, "foo", "bar")))
+				Expect(result[1].Path.String()).To(Equal("/spec"))
+				Expect(result[1].Details).To(HaveLen(1))
+				Expect(result[1].Details[0].Kind).To(Equal(dyff.REMOVAL))
+			})
+		})
+
+		Context("comparing with URL normalization", func() {
+			It("should not report URLs that only differ by a trailing slash or default port", func() {
+				result, err := compare(
+					yml(`{endpoint: "http://example.org:80/", api: "HTTPS://Example.org:443/v1/"}`),
+					yml(`{endpoint: "http://example.org", api: "https://example.org/v1"}`),
+					dyff.NormalizeURLPaths("/endpoint", "api"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report URLs with a different port, path, or at other paths", func() {
+				result, err := compare(
+					yml(`{endpoint: "http://example.org:8080/", api: "http://example.org/v1", other: "http://example.org/"}`),
+					yml(`{endpoint: "http://example.org/", api: "http://example.org/v2", other: "http://example.org"}`),
+					dyff.NormalizeURLPaths("/endpoint", "/api"),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+			})
+
+			It("should compare values that are no URLs as strings", func() {
+				result, err := compare(yml(`{endpoint: "foo/"}`), yml(`{endpoint: "foo"}`), dyff.NormalizeURLPaths("/endpoint"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+
+			It("should panic for paths that cannot be parsed", func() {
+				Expect(func() { dyff.NormalizeURLPaths("/endpoint=a=b") }).To(PanicWith(ContainSubstring(`NormalizeURLPaths("/endpoint=a=b")`)))
+			})
+		})
+
+		Context("comparing Go values", func() {
+			type container struct {
+				Name  string `yaml:"name"`
+				Image string `yaml:"image"`
+			}
+
+			type deployment struct {
+				Name       string      `yaml:"name"`
+				Replicas   int         `yaml:"replicas"`
+				Containers []container `yaml:"containers"`
+			}
+
+			It("should compare two maps", func() {
+				report, err := dyff.CompareGoValues(
+					map[string]interface{}{"name": "foo", "ports": []int{80}},
+					map[string]interface{}{"name": "foo", "ports": []int{80, 443}},
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/ports", dyff.ADDITION, nil, list(`[443]`))))
+			})
+
+			It("should compare two structs with a changed field", func() {
+				report, err := dyff.CompareGoValues(
+					deployment{Name: "app", Replicas: 1, Containers: []container{{Name: "app", Image: "app:1.0"}}},
+					deployment{Name: "app", Replicas: 1, Containers: []container{{Name: "app", Image: "app:2.0"}}},
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/containers/name=app/image", dyff.MODIFICATION, "app:1.0", "app:2.0")))
+			})
+
+			It("should fail for values that cannot be marshalled", func() {
+				_, err := dyff.CompareGoValues(map[string]interface{}{"foo": unmarshallable{}}, nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("failed to marshal from value"))
+			})
+		})
+
+		Context("matching similar list entries", func() {
+			from := yml(`---
+list:
+- name: foo
+  image: app
+  version: 1
+  port: 80
+- other: entry
+`)
+			to := yml(`---
+list:
+- name: foo
+  image: app
+  version: 2
+  port: 80
+- other: entry
+`)
+
+			It("should report a modification when the entries are similar enough", func() {
+				result, err := compare(from, to, dyff.SequenceMatchThreshold(0.5))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list/0/version", dyff.MODIFICATION, 1, 2)))
+			})
+
+			It("should report a removal and an addition when the threshold demands a closer match", func() {
+				result, err := compare(from, to, dyff.SequenceMatchThreshold(0.9))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[0].Details[1].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should not pair entries without the option", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+		})
+
+		Context("comparing renamed paths", func() {
+			from := yml(`---
+spec:
+  foo: 1
+  other: value
+`)
+
+			It("should report a changed value of a renamed path as a modification", func() {
+				to := yml(`---
+spec:
+  bar: 2
+  other: value
+`)
+
+				result, err := compare(from, to, dyff.PathAliases(map[string]string{"spec.foo": "spec.bar"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/bar", dyff.MODIFICATION, 1, 2)))
+			})
+
+			It("should report no difference for an unchanged value of a renamed path", func() {
+				to := yml(`---
+spec:
+  other: value
+  settings:
+    bar: 1
+`)
+
+				result, err := compare(from, to, dyff.PathAliases(map[string]string{"/spec/foo": "/spec/settings/bar"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not change the input documents", func() {
+				to := yml(`---
+spec:
+  bar: 1
+  other: value
+`)
+
+				_, err := compare(from, to, dyff.PathAliases(map[string]string{"spec.foo": "spec.bar"}))
+				Expect(err).ToNot(HaveOccurred())
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+
+			It("should fail for aliases that do not end in a map key", func() {
+				_, err := compare(from, from, dyff.PathAliases(map[string]string{"/spec/foo": "/"}))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("limiting the number of differences per document", func() {
+			It("should only report the first differences of a document that hits the limit", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{a: 1, b: 1, c: 1, d: 1}",
+					"{a: 1, b: 1}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{a: 2, b: 2, c: 2, d: 2}",
+					"{a: 2, b: 2}",
+				)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.MaxDiffsPerDocument(2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(4))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("#0/a", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("#0/b", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Diffs[2]).To(BeSameDiffAs(singleDiff("#1/a", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Diffs[3]).To(BeSameDiffAs(singleDiff("#1/b", dyff.MODIFICATION, 1, 2)))
+				Expect(report.Warnings).To(Equal([]string{
+					"document #1 has 4 differences, only the first 2 are reported",
+				}))
+			})
+
+			It("should show the note about the differences that are not reported in the human report", func() {
+				from := ytbx.InputFile{Documents: multiDoc("{a: 1, b: 1, c: 1}")}
+				to := ytbx.InputFile{Documents: multiDoc("{a: 2, b: 2, c: 2}")}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.MaxDiffsPerDocument(1))
+				Expect(err).ToNot(HaveOccurred())
+
+				var buf bytes.Buffer
+				Expect((&dyff.HumanReport{Report: report, OmitHeader: true}).WriteReport(&buf)).To(Succeed())
+				Expect(buf.String()).To(HavePrefix("\n⚠ document #1 has 3 differences, only the first 1 are reported\n\na\n"))
+			})
+		})
+
+		Context("comparing templates with rendered output", func() {
+			from := yml(`---
+metadata:
+  name: "{{ .Release.Name }}-app"
+spec:
+  replicas: "{{ .Values.replicas }}"
+  image: app:1.0
+  resources: "{{ toYaml .Values.resources }}"
+`)
+
+			It("should match placeholders with any rendered value", func() {
+				to := yml(`---
+metadata:
+  name: "{{ .Release.Name }}-app"
+spec:
+  replicas: 3
+  image: app:1.0
+  resources:
+    limits: {cpu: 1}
+`)
+
+				result, err := compare(from, to, dyff.TemplatePlaceholderPattern(`^\{\{.*\}\}$`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should still report changes of values that are not placeholders", func() {
+				to := yml(`---
+metadata:
+  name: release-app
+spec:
+  replicas: 3
+  image: app:2.0
+  resources: {}
+`)
+
+				result, err := compare(from, to, dyff.TemplatePlaceholderPattern(`^\{\{.*\}\}$`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/metadata/name", dyff.MODIFICATION, "{{ .Release.Name }}-app", "release-app")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/spec/image", dyff.MODIFICATION, "app:1.0", "app:2.0")))
+			})
+		})
+
+		Context("matching list entries with field weights", func() {
+			from := yml(`---
+list:
+- {name: web, image: nginx, port: 80, tier: frontend}
+- {other: entry}
+`)
+			to := yml(`---
+list:
+- {name: web, image: httpd, port: 81, tier: backend}
+- {name: api, image: nginx, port: 80, tier: frontend}
+- {other: entry}
+`)
+
+			It("should pair the entries with the most equal fields without weights", func() {
+				result, err := compare(from, to, dyff.SequenceMatchThreshold(0.5))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list", dyff.ADDITION, nil, list(`[{name: web, image: httpd, port: 81, tier: backend}]`))))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/0/name", dyff.MODIFICATION, "web", "api")))
+			})
+
+			It("should pair the entries with the most important equal fields with weights", func() {
+				result, err := compare(from, to, dyff.SequenceMatchThreshold(0.5), dyff.FieldWeights(map[string]float64{"/name": 10}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(4))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list", dyff.ADDITION, nil, list(`[{name: api, image: nginx, port: 80, tier: frontend}]`))))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/0/image", dyff.MODIFICATION, "nginx", "httpd")))
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/list/0/port", dyff.MODIFICATION, 80, 81)))
+				Expect(result[3]).To(BeSameDiffAs(singleDiff("/list/0/tier", dyff.MODIFICATION, "frontend", "backend")))
+			})
+		})
+
+		Context("reporting position changes of list entries", func() {
+			It("should report each moved entry of a simple list with its old and new index", func() {
+				from := yml(`{list: [a, b, c]}`)
+				to := yml(`{list: [c, a, b]}`)
+
+				result, err := compare(from, to, dyff.ReportPositionChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list/0", dyff.POSITIONCHANGE, 0, 1)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/1", dyff.POSITIONCHANGE, 1, 2)))
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/list/2", dyff.POSITIONCHANGE, 2, 0)))
+			})
+
+			It("should report position changes together with paired similar entries", func() {
+				from := yml(`{list: [a, b, c, {x: 1, y: 2, z: 3}]}`)
+				to := yml(`{list: [c, a, b, {x: 1, y: 2, z: 4}]}`)
+
+				result, err := compare(from, to, dyff.ReportPositionChanges(true), dyff.SequenceMatchThreshold(0.5))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(4))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list/0", dyff.POSITIONCHANGE, 0, 1)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/1", dyff.POSITIONCHANGE, 1, 2)))
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/list/2", dyff.POSITIONCHANGE, 2, 0)))
+				Expect(result[3]).To(BeSameDiffAs(singleDiff("/list/3/z", dyff.MODIFICATION, 3, 4)))
+			})
+
+			It("should report an order change without the option", func() {
+				result, err := compare(yml(`{list: [a, b, c]}`), yml(`{list: [c, a, b]}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+			})
+
+			It("should only report modifications for moved entries with a changed content", func() {
+				from := yml(`---
+list:
+- name: one
+  value: 1
+- name: two
+  value: 2
+- name: three
+  value: 3
+`)
+				to := yml(`---
+list:
+- name: two
+  value: 2
+- name: one
+  value: 100
+- name: three
+  value: 3
+`)
+
+				result, err := compare(from, to, dyff.ReportPositionChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list/name=one/value", dyff.MODIFICATION, 1, 100)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/name=two", dyff.POSITIONCHANGE, 1, 0)))
+			})
+
+			It("should treat position changes like order changes in the report functions", func() {
+				from := ytbx.InputFile{Documents: multiDoc(`{list: [a, b, c]}`)}
+				to := ytbx.InputFile{Documents: multiDoc(`{list: [c, a, b]}`)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.ReportPositionChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(3))
+				Expect(report.Similarity()).To(Equal(1.0))
+
+				_, _, _, orderChanges := report.GroupByKind()
+				Expect(orderChanges).To(HaveLen(3))
+
+				var buf bytes.Buffer
+				Expect((&dyff.EventStreamReport{Report: report}).WriteReport(&buf)).To(Succeed())
+				Expect(buf.String()).To(HavePrefix(`{"type":"moved","path":"list.0","from":0,"to":1}` + "\n"))
+
+				buf.Reset()
+				Expect((&dyff.HumanReport{Report: report, OmitHeader: true, ShowFooter: true}).WriteReport(&buf)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring("(0 +, 0 -, 0 ±, 3 ↕)"))
+			})
+		})
+
+		Context("checking whether inputs are equal", func() {
+			It("should return true for inputs without differences", func() {
+				from := ytbx.InputFile{Documents: multiDoc(`{name: foo, list: [a, b]}`, `{name: bar}`)}
+				to := ytbx.InputFile{Documents: multiDoc(`{list: [a, b], name: foo}`, `{name: bar}`)}
+
+				equal, err := dyff.Equal(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(equal).To(BeTrue())
+			})
+
+			It("should return false for inputs with differences", func() {
+				from := ytbx.InputFile{Documents: multiDoc(`{name: foo, list: [a, b]}`, `{name: bar}`)}
+				to := ytbx.InputFile{Documents: multiDoc(`{name: foo, list: [a, b]}`, `{name: baz}`)}
+
+				equal, err := dyff.Equal(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(equal).To(BeFalse())
+			})
+
+			It("should return false for Kubernetes resources that only exist on one side", func() {
+				from := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: foo}}",
+				)}
+
+				to := ytbx.InputFile{Documents: multiDoc(
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: foo}}",
+					"{apiVersion: v1, kind: ConfigMap, metadata: {name: bar}}",
+				)}
+
+				equal, err := dyff.Equal(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(equal).To(BeFalse())
+			})
+
+			It("should consider options that filter the differences", func() {
+				from := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 1}`)}
+				to := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 2}`)}
+
+				equal, err := dyff.Equal(from, to, dyff.KeysOnly(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(equal).To(BeTrue())
+
+				equal, err = dyff.Equal(from, to, dyff.IgnorePaths("/replicas"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(equal).To(BeTrue())
+			})
+		})
+
+		Context("matching map keys case-insensitive", func() {
+			from := yml(`---
+request:
+  headers:
+    Content-Type: application/json
+    Accept: text/plain
+  labels:
+    App: foo
+`)
+
+			It("should match the keys of the listed maps regardless of their case", func() {
+				to := yml(`---
+request:
+  headers:
+    content-type: application/json
+    ACCEPT: text/html
+  labels:
+    App: foo
+`)
+
+				result, err := compare(from, to, dyff.CaseInsensitiveKeyPaths("request.headers"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/request/headers/Accept", dyff.MODIFICATION, "text/plain", "text/html")))
+			})
+
+			It("should still report keys with a different case in other maps", func() {
+				to := yml(`---
+request:
+  headers:
+    content-type: application/json
+    Accept: text/plain
+  labels:
+    app: foo
+`)
+
+				result, err := compare(from, to, dyff.CaseInsensitiveKeyPaths("/request/headers"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(doubleDiff("/request/labels",
+					dyff.REMOVAL, yml(`{App: foo}`), nil,
+					dyff.ADDITION, nil, yml(`{app: foo}`),
+				)))
+			})
+
+			It("should panic for paths that cannot be parsed", func() {
+				Expect(func() { dyff.CaseInsensitiveKeyPaths("/request=a=b") }).To(PanicWith(ContainSubstring(`CaseInsensitiveKeyPaths("/request=a=b")`)))
+			})
+		})
 	})
 })
@@ -21,8 +21,13 @@
 package dyff
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	pathpkg "path"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/gonvenience/bunt"
@@ -33,7 +38,13 @@ import (
 	yamlv3 "gopkg.in/yaml.v3"
 )
 
-// CompareOption sets a specific compare setting for the object comparison
+// CompareOption sets a specific compare setting for the object comparison.
+// Options can be combined freely and are applied in the given order. Without
+// any options, the comparison uses these defaults: Kubernetes entity detection
+// (KubernetesEntityDetection) is enabled, order changes in lists are reported,
+// a non-standard list entry identifier is only guessed for lists with at least
+// three entries (NonStandardIdentifierGuessCountThreshold), the input format is
+// detected automatically, and all other options are disabled.
 type CompareOption func(*compareSettings)
 
 type compareSettings struct {
@@ -41,12 +52,58 @@ type compareSettings struct {
 	IgnoreOrderChanges                       bool
 	KubernetesEntityDetection                bool
 	AdditionalIdentifiers                    []ListItemIdentifierField
+	Format                                   InputFormat
+	DocumentSelector                         []int
+	NullEqualsMissing                        bool
+	EmptyCollectionsEqualAbsent              bool
+	CoerceStringNumbers                      bool
+	CanonicalizeNumbers                      bool
+	IgnorePaths                              []string
+	MaxCompareDepth                          int
+	Comparators                              []pathComparator
+	RootPath                                 string
+	MergeKeys                                map[string]string
+	KeysOnly                                 bool
+	KindAllowlist                            []string
+	KindDenylist                             []string
+	MultisetPaths                            []string
+	IgnoreTagOnlyChanges                     bool
+	SuppressKinds                            []rune
+	IgnoreAppends                            bool
+	PreprocessFunc                           func(*yamlv3.Node) (*yamlv3.Node, error)
+	DetectDuplicateKeys                      bool
+	IgnoreAnnotationPrefixes                 []string
+	NormalizeSingletonLists                  bool
+	IgnoreValuePatterns                      []*regexp.Regexp
+	ScalarOnly                               bool
+	SubsetMode                               bool
+	NormalizeURLPaths                        []string
+	SequenceMatchThreshold                   float64
+	PathAliases                              map[string]string
+	MaxDiffsPerDocument                      int
+	TemplatePlaceholderPattern               *regexp.Regexp
+	FieldWeights                             map[string]float64
+	ReportPositionChanges                    bool
+	CaseInsensitiveKeyPaths                  []string
+}
+
+type pathComparator struct {
+	pattern string
+	equal   func(from, to *yamlv3.Node) bool
 }
 
 type compare struct {
 	settings compareSettings
+
+	// stopAtFirstDiff aborts the comparison with errDifferenceFound as soon as
+	// the first difference is found (see Equal)
+	stopAtFirstDiff bool
 }
 
+// errDifferenceFound is returned by the comparison functions in case the
+// comparison is configured to stop at the first difference
+var errDifferenceFound = errors.New("difference found")
+
 // ListItemIdentifierField names the field that identifies a list.
 type ListItemIdentifierField string
 
@@ -84,122 +141,1244 @@ func KubernetesEntityDetection(value bool) CompareOption {
 	}
 }
 
-// CompareInputFiles is one of the convenience main entry points for comparing
-// objects. In this case the representation of an input file, which might
-// contain multiple documents. It returns a report with the list of differences.
-func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (Report, error) {
-	// initialize the comparator with the tool defaults
-	cmpr := compare{
-		settings: compareSettings{
-			NonStandardIdentifierGuessCountThreshold: 3,
-			IgnoreOrderChanges:                       false,
-			KubernetesEntityDetection:                true,
-		},
+// newCompare returns a comparator with the tool defaults and the optional
+// compare options applied
+func newCompare(compareOptions ...CompareOption) compare {
+	// initialize the comparator with the tool defaults
+	cmpr := compare{
+		settings: compareSettings{
+			NonStandardIdentifierGuessCountThreshold: 3,
+			IgnoreOrderChanges:                       false,
+			KubernetesEntityDetection:                true,
+		},
+	}
+
+	// apply the optional compare options provided to this function call
+	for _, compareOption := range compareOptions {
+		compareOption(&cmpr.settings)
+	}
+
+	return cmpr
+}
+
+// NullEqualsMissing treats a map entry with an explicit null value as equal to
+// a missing map entry, so that neither is reported as an addition or removal
+func NullEqualsMissing(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.NullEqualsMissing = value
+	}
+}
+
+// EmptyCollectionsEqualAbsent treats a map entry with an empty map or empty
+// list as its value as equal to a missing map entry
+func EmptyCollectionsEqualAbsent(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.EmptyCollectionsEqualAbsent = value
+	}
+}
+
+// CoerceStringNumbers treats a string that cleanly parses as a number as equal
+// to a number with the same value, for example "8080" and 8080
+func CoerceStringNumbers(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.CoerceStringNumbers = value
+	}
+}
+
+// IgnoreTagOnlyChanges treats two scalars with the same literal value as equal,
+// even if their YAML tags differ, for example "123" and 123
+func IgnoreTagOnlyChanges(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.IgnoreTagOnlyChanges = value
+	}
+}
+
+// SuppressKinds skips the given detail kinds (for example ORDERCHANGE or
+// ADDITION) while walking the documents, so that they are never generated in
+// the first place rather than being filtered out of the final report
+func SuppressKinds(kinds ...rune) CompareOption {
+	return func(settings *compareSettings) {
+		settings.SuppressKinds = append(settings.SuppressKinds, kinds...)
+	}
+}
+
+// CanonicalizeNumbers compares numbers by their numeric value instead of their
+// literal representation, so that for example 1e3, 1000, and 1000.0 as well as
+// 0x10 and 16 are considered equal
+func CanonicalizeNumbers(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.CanonicalizeNumbers = value
+	}
+}
+
+// IgnorePaths specifies paths (in Go-Patch or Dot-Style), which are excluded
+// from the comparison including everything below them. It panics if one of the
+// paths cannot be parsed.
+func IgnorePaths(paths ...string) CompareOption {
+	ignorePaths := mustParsePaths("IgnorePaths", paths)
+	return func(settings *compareSettings) {
+		settings.IgnorePaths = append(settings.IgnorePaths, ignorePaths...)
+	}
+}
+
+// mustParsePaths parses the paths (in Go-Patch or Dot-Style) and returns them
+// in their Go-Patch string representation, it panics like regexp.MustCompile
+// if a path cannot be parsed instead of silently ignoring it
+func mustParsePaths(option string, pathStrings []string) []string {
+	result := make([]string, 0, len(pathStrings))
+	for _, pathString := range pathStrings {
+		path, err := ytbx.ParsePathStringUnsafe(pathString)
+		if err != nil {
+			panic(fmt.Sprintf("dyff: %s(%q): %v", option, pathString, err))
+		}
+
+		result = append(result, path.String())
+	}
+
+	return result
+}
+
+// MultisetPaths specifies paths (in Go-Patch or Dot-Style) of lists, which are
+// compared as multisets: The order of the entries is irrelevant, but the number
+// of occurrences of each entry counts, so that each surplus occurrence on one
+// side is reported as an addition or removal. It panics if one of the paths
+// cannot be parsed.
+func MultisetPaths(paths ...string) CompareOption {
+	multisetPaths := mustParsePaths("MultisetPaths", paths)
+	return func(settings *compareSettings) {
+		settings.MultisetPaths = append(settings.MultisetPaths, multisetPaths...)
+	}
+}
+
+// IgnoreAppends ignores entries that were only appended to the end of a list,
+// while insertions in the middle of a list or removals are still reported
+func IgnoreAppends(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.IgnoreAppends = value
+	}
+}
+
+// PreprocessFunc sets a function, which is applied to each document of both
+// input files before the comparison, for example to canonicalize documents
+func PreprocessFunc(fn func(*yamlv3.Node) (*yamlv3.Node, error)) CompareOption {
+	return func(settings *compareSettings) {
+		settings.PreprocessFunc = fn
+	}
+}
+
+// DetectDuplicateKeys adds a warning to the report for each map with duplicate
+// keys in the input files, since only one of the values can be compared
+func DetectDuplicateKeys(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DetectDuplicateKeys = value
+	}
+}
+
+// IgnoreAnnotationPrefixes ignores changes of Kubernetes annotations (map keys
+// under `metadata.annotations` at any level, for example also in the pod
+// template of a deployment) that start with any of the given prefixes
+func IgnoreAnnotationPrefixes(prefixes ...string) CompareOption {
+	return func(settings *compareSettings) {
+		settings.IgnoreAnnotationPrefixes = append(settings.IgnoreAnnotationPrefixes, prefixes...)
+	}
+}
+
+// NormalizeSingletonLists considers a value and a list with only this one value
+// to be equal, for example `port: 80` and `port: [80]`
+func NormalizeSingletonLists(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.NormalizeSingletonLists = value
+	}
+}
+
+// IgnoreValuePatterns ignores modifications of scalar values, where both the
+// old and the new value match any of the given regular expressions, for
+// example generated hashes. It panics if a pattern cannot be compiled.
+func IgnoreValuePatterns(patterns ...string) CompareOption {
+	return func(settings *compareSettings) {
+		for _, pattern := range patterns {
+			settings.IgnoreValuePatterns = append(settings.IgnoreValuePatterns, regexp.MustCompile(pattern))
+		}
+	}
+}
+
+// TemplatePlaceholderPattern treats a scalar value of the from document, which
+// matches the given regular expression, as a placeholder that is equal to any
+// value at the same path of the to document, for example to compare a template
+// with `{{ .Values.replicas }}` placeholders against its rendered output. It
+// panics if the pattern cannot be compiled.
+func TemplatePlaceholderPattern(pattern string) CompareOption {
+	return func(settings *compareSettings) {
+		settings.TemplatePlaceholderPattern = regexp.MustCompile(pattern)
+	}
+}
+
+// KubernetesDefaults is a preset that ignores the well-known Kubernetes fields,
+// which are maintained by the cluster and change with every update of a
+// resource: `metadata.managedFields`, `metadata.generation`,
+// `metadata.resourceVersion`, and `status`
+func KubernetesDefaults() CompareOption {
+	return IgnorePaths(
+		"/metadata/managedFields",
+		"/metadata/generation",
+		"/metadata/resourceVersion",
+		"/status",
+	)
+}
+
+// MaxCompareDepth limits how deep the comparison descends into the documents.
+// Maps and lists at the maximum depth are not compared entry by entry, but are
+// reported as one modification in case they differ. Zero means unlimited.
+func MaxCompareDepth(depth int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.MaxCompareDepth = depth
+	}
+}
+
+// RootPath restricts the comparison to the subtree at the provided Go-Patch
+// style path in each document. Paths in the report are relative to the subtree
+// and in case the subtree only exists on one side, it is reported as a whole.
+func RootPath(path string) CompareOption {
+	return func(settings *compareSettings) {
+		settings.RootPath = path
+	}
+}
+
+// MergeKeys specifies the identifier to be used for lists at the given paths,
+// similar to the patch merge key of Kubernetes strategic merge patches. The
+// map keys are Go-Patch style path patterns of the lists, where `*` matches any
+// single path element, and the map values are the identifying field names. A
+// merge key takes precedence over the default identifier detection, as long as
+// all list entries on both sides have a unique value for it. If more than one
+// pattern matches, the most specific one is used.
+func MergeKeys(mergeKeys map[string]string) CompareOption {
+	return func(settings *compareSettings) {
+		if settings.MergeKeys == nil {
+			settings.MergeKeys = map[string]string{}
+		}
+
+		for pattern, key := range mergeKeys {
+			settings.MergeKeys[pattern] = key
+		}
+	}
+}
+
+// PathAliases specifies fields that were renamed between the two inputs. The
+// map keys are the old paths in the from input, the map values are the new
+// paths in the to input (both in Go-Patch or dot-style). Before comparing, the
+// value at an old path is moved to the new path, so that a renamed field is
+// compared with its new counterpart instead of being reported as a removal and
+// an addition. Only paths ending in a map key can be renamed.
+func PathAliases(aliases map[string]string) CompareOption {
+	return func(settings *compareSettings) {
+		if settings.PathAliases == nil {
+			settings.PathAliases = map[string]string{}
+		}
+
+		for oldPath, newPath := range aliases {
+			settings.PathAliases[oldPath] = newPath
+		}
+	}
+}
+
+// KeysOnly restricts the report to the addition and removal of map entries
+// (keys), so that changes of values or orders are not reported
+func KeysOnly(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.KeysOnly = value
+	}
+}
+
+// ScalarOnly reports modifications of scalar values in detail, but summarizes
+// structural differences (maps with different keys or lists with a different
+// number of entries) as the removal of the old and the addition of the new
+// map or list at the parent path instead of reporting each entry
+func ScalarOnly(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.ScalarOnly = value
+	}
+}
+
+// SubsetMode checks whether `to` is a superset of `from`: Only removals and
+// modifications of the content of `from` are reported, whereas additional map
+// entries, list entries, or documents in `to` are accepted
+func SubsetMode(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.SubsetMode = value
+	}
+}
+
+// NormalizeURLPaths specifies paths (in Go-Patch or Dot-Style) of URL values,
+// which are compared after a normalization: The scheme and host are compared
+// case-insensitive, default ports and trailing slashes are not considered a
+// difference. Values that are no absolute URLs are compared as strings. It
+// panics if one of the paths cannot be parsed.
+func NormalizeURLPaths(paths ...string) CompareOption {
+	urlPaths := mustParsePaths("NormalizeURLPaths", paths)
+	return func(settings *compareSettings) {
+		settings.NormalizeURLPaths = append(settings.NormalizeURLPaths, urlPaths...)
+	}
+}
+
+// SequenceMatchThreshold pairs removed and added entries of lists without
+// identifiers if their similarity (the share of equal leaf values, between 0
+// and 1) is at least the given threshold, so that they are reported as a
+// modification instead of a removal and an addition. Higher values demand
+// closer matches, zero (the default) disables the pairing.
+func SequenceMatchThreshold(threshold float64) CompareOption {
+	return func(settings *compareSettings) {
+		settings.SequenceMatchThreshold = threshold
+	}
+}
+
+// MaxDiffsPerDocument limits the report to the first given number of
+// differences of each document, so that one document with many differences
+// does not crowd out the others. For each document with more differences, a
+// warning is added to the report. Zero (the default) disables the limit.
+func MaxDiffsPerDocument(limit int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.MaxDiffsPerDocument = limit
+	}
+}
+
+// FieldWeights specifies how much the values at the given paths count when the
+// similarity of two entries of a list without identifiers is calculated (see
+// SequenceMatchThreshold). The map keys are Go-Patch style path patterns
+// relative to the list entry, where `*` matches any single path element, and
+// the map values are the weights. Paths without a weight have a weight of one.
+// Use the same weights with Report.WeightedSimilarity for the report.
+func FieldWeights(weights map[string]float64) CompareOption {
+	return func(settings *compareSettings) {
+		if settings.FieldWeights == nil {
+			settings.FieldWeights = map[string]float64{}
+		}
+
+		for pattern, weight := range weights {
+			settings.FieldWeights[pattern] = weight
+		}
+	}
+}
+
+// ReportPositionChanges reports list entries, which moved to another index
+// without changing their content, with one POSITIONCHANGE detail per entry
+// instead of one ORDERCHANGE detail for the whole list. The detail is located
+// at the path of the list entry and has the old and new index as values.
+// Entries with a changed content are only reported with their modifications.
+func ReportPositionChanges(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.ReportPositionChanges = value
+	}
+}
+
+// CaseInsensitiveKeyPaths specifies paths (in Go-Patch or Dot-Style) of maps,
+// where keys are matched case-insensitive, for example maps of HTTP headers, so
+// that `Content-Type` and `content-type` are compared with each other instead
+// of being reported as a removal and an addition. It panics if one of the paths
+// cannot be parsed.
+func CaseInsensitiveKeyPaths(paths ...string) CompareOption {
+	keyPaths := mustParsePaths("CaseInsensitiveKeyPaths", paths)
+	return func(settings *compareSettings) {
+		settings.CaseInsensitiveKeyPaths = append(settings.CaseInsensitiveKeyPaths, keyPaths...)
+	}
+}
+
+// KindAllowlist restricts the report to differences in documents with one of
+// the given Kubernetes resource kinds (based on the `kind` field). Differences
+// in documents without a kind are dropped.
+func KindAllowlist(kinds ...string) CompareOption {
+	return func(settings *compareSettings) {
+		settings.KindAllowlist = append(settings.KindAllowlist, kinds...)
+	}
+}
+
+// KindDenylist drops differences in documents with one of the given Kubernetes
+// resource kinds (based on the `kind` field), for example noisy resources like
+// `Event` or `Lease`. Differences in documents without a kind are kept.
+func KindDenylist(kinds ...string) CompareOption {
+	return func(settings *compareSettings) {
+		settings.KindDenylist = append(settings.KindDenylist, kinds...)
+	}
+}
+
+// RegisterComparator registers a custom equality function for all paths that
+// match the provided Go-Patch style path pattern, where `*` matches any single
+// path element (for example `/spec/containers/*/image`). In case the function
+// reports the values as equal, no difference is reported for the path. If more
+// than one pattern matches, the most specific one (the one with the most path
+// elements without wildcards) is used.
+func RegisterComparator(pathPattern string, equal func(from, to *yamlv3.Node) bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.Comparators = append(settings.Comparators, pathComparator{
+			pattern: pathPattern,
+			equal:   equal,
+		})
+	}
+}
+
+// DocumentSelector restricts the comparison to the documents with the given
+// indices (starting with zero) in both input files. The differences are
+// reported with the original index of their document.
+func DocumentSelector(indices ...int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DocumentSelector = append(settings.DocumentSelector, indices...)
+	}
+}
+
+// CompareBytes is one of the convenience main entry points for comparing
+// objects. In this case the raw input data, which is parsed using the input
+// format configured by the Format compare option (defaults to an automatic
+// detection of YAML, JSON, or TOML). It returns a report with the list of
+// differences.
+func CompareBytes(from []byte, to []byte, compareOptions ...CompareOption) (Report, error) {
+	cmpr := newCompare(compareOptions...)
+
+	fromDocuments, err := loadDocuments(from, cmpr.settings.Format)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to load from input: %w", err)
+	}
+
+	toDocuments, err := loadDocuments(to, cmpr.settings.Format)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to load to input: %w", err)
+	}
+
+	return CompareInputFiles(
+		ytbx.InputFile{Documents: fromDocuments},
+		ytbx.InputFile{Documents: toDocuments},
+		compareOptions...,
+	)
+}
+
+// CompareGoValues is one of the convenience main entry points for comparing
+// objects. In this case arbitrary Go values, which are converted into YAML
+// documents using the YAML marshalling (honoring `yaml` struct tags). It
+// returns a report with the list of differences.
+func CompareGoValues(from interface{}, to interface{}, compareOptions ...CompareOption) (Report, error) {
+	load := func(value interface{}, side string) (ytbx.InputFile, error) {
+		data, err := yamlv3.Marshal(value)
+		if err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to marshal %s value: %w", side, err)
+		}
+
+		var document yamlv3.Node
+		if err := yamlv3.Unmarshal(data, &document); err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to unmarshal %s value: %w", side, err)
+		}
+
+		return ytbx.InputFile{Documents: []*yamlv3.Node{&document}}, nil
+	}
+
+	fromInputFile, err := load(from, "from")
+	if err != nil {
+		return Report{}, err
+	}
+
+	toInputFile, err := load(to, "to")
+	if err != nil {
+		return Report{}, err
+	}
+
+	return CompareInputFiles(fromInputFile, toInputFile, compareOptions...)
+}
+
+// CompareInputFiles is one of the convenience main entry points for comparing
+// objects. In this case the representation of an input file, which might
+// contain multiple documents. It returns a report with the list of differences.
+func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (Report, error) {
+	cmpr := newCompare(compareOptions...)
+
+	report, err := cmpr.inputFiles(from, to)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if cmpr.settings.DetectDuplicateKeys {
+		report.Warnings = append(report.Warnings, duplicateKeyWarnings("from", report.From)...)
+		report.Warnings = append(report.Warnings, duplicateKeyWarnings("to", report.To)...)
+	}
+
+	if cmpr.settings.KeysOnly {
+		report = report.MapDetails(func(_ Diff, detail Detail) (Detail, bool) {
+			return detail, isKeyChange(detail)
+		})
+	}
+
+	if len(cmpr.settings.KindAllowlist) > 0 {
+		report = report.filterByKind(func(kind string) bool {
+			for _, allowed := range cmpr.settings.KindAllowlist {
+				if kind == allowed {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+
+	if len(cmpr.settings.KindDenylist) > 0 {
+		report = report.filterByKind(func(kind string) bool {
+			if kind == "" {
+				return true
+			}
+
+			for _, denied := range cmpr.settings.KindDenylist {
+				if kind == denied {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+
+	if cmpr.settings.MaxDiffsPerDocument > 0 {
+		report = report.limitPerDocument(cmpr.settings.MaxDiffsPerDocument)
+	}
+
+	return report, nil
+}
+
+// Equal returns whether the two input files are equal, i.e. whether their
+// comparison with the given options yields no differences. Other than
+// CompareInputFiles, it stops at the first difference instead of collecting
+// all of them, unless an option filters the differences after the comparison.
+func Equal(from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (bool, error) {
+	cmpr := newCompare(compareOptions...)
+
+	// options that drop differences from the report require the complete list
+	// of differences to decide whether there are any differences left
+	if cmpr.settings.KeysOnly || len(cmpr.settings.KindAllowlist) > 0 || len(cmpr.settings.KindDenylist) > 0 {
+		report, err := CompareInputFiles(from, to, compareOptions...)
+		if err != nil {
+			return false, err
+		}
+
+		return !report.HasChanges(), nil
+	}
+
+	cmpr.stopAtFirstDiff = true
+	report, err := cmpr.inputFiles(from, to)
+	switch {
+	case errors.Is(err, errDifferenceFound):
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	return !report.HasChanges(), nil
+}
+
+// isKeyChange returns whether the detail is the addition or removal of map
+// entries or whole documents
+func isKeyChange(detail Detail) bool {
+	switch detail.Kind {
+	case ADDITION:
+		return detail.To != nil && (detail.To.Kind == yamlv3.MappingNode || detail.To.Kind == yamlv3.DocumentNode)
+
+	case REMOVAL:
+		return detail.From != nil && (detail.From.Kind == yamlv3.MappingNode || detail.From.Kind == yamlv3.DocumentNode)
+	}
+
+	return false
+}
+
+func (compare *compare) inputFiles(from ytbx.InputFile, to ytbx.InputFile) (Report, error) {
+	// in case only specific documents are selected, reduce both input files to
+	// the selected documents before doing anything else
+	if len(compare.settings.DocumentSelector) > 0 {
+		var err error
+		if from, err = selectDocuments(from, compare.settings.DocumentSelector); err != nil {
+			return Report{}, err
+		}
+
+		if to, err = selectDocuments(to, compare.settings.DocumentSelector); err != nil {
+			return Report{}, err
+		}
+	}
+
+	// in case a preprocessing function is configured, apply it to the documents
+	// of both input files before comparing them
+	if compare.settings.PreprocessFunc != nil {
+		var err error
+		if from, err = preprocess(from, "from", compare.settings.PreprocessFunc); err != nil {
+			return Report{}, err
+		}
+
+		if to, err = preprocess(to, "to", compare.settings.PreprocessFunc); err != nil {
+			return Report{}, err
+		}
+	}
+
+	// in case path aliases are configured, move the values of the old paths to
+	// the new paths in the from input file, so that they are compared directly
+	if len(compare.settings.PathAliases) > 0 {
+		var err error
+		if from, err = renamePaths(from, compare.settings.PathAliases); err != nil {
+			return Report{}, err
+		}
+	}
+
+	// in case a root path is configured, only the subtrees at that path are
+	// compared, which makes a Kubernetes document look-up by name impossible
+	if compare.settings.RootPath != "" {
+		return compare.subtrees(from, to)
+	}
+
+	// in case Kubernetes mode is enabled, try to compare documents in the YAML
+	// file by their names rather than just by the order of the documents
+	if compare.settings.KubernetesEntityDetection {
+		// when the look-up of a name for each document in each file worked out, it
+		// means that the documents are most likely Kubernetes resources, so a comparison
+		// using the names can be done, otherwise, leave and continue with default behavior
+		fromNames, fromOK := documentNames(from)
+		toNames, toOK := documentNames(to)
+		if fromOK && toOK {
+			// Keep the empty documents in place, so that the differences are
+			// reported with the original index of their document
+			from.Names, to.Names = fromNames, toNames
+
+			// Compare the document nodes, in case of an error it will fall back to the default
+			// implementation and continue to compare the files without any special semantics
+			result, err := compare.documentNodes(from, to)
+			switch {
+			case err == nil:
+				return Report{From: from, To: to, Diffs: result}, nil
+
+			case errors.Is(err, errDifferenceFound):
+				return Report{}, err
+			}
+		}
+	}
+
+	if len(from.Documents) != len(to.Documents) {
+		return Report{}, fmt.Errorf("comparing YAMLs with a different number of documents is currently not supported")
+	}
+
+	var result []Diff
+	for idx := range from.Documents {
+		diffs, err := compare.objects(
+			ytbx.Path{
+				Root:        &from,
+				DocumentIdx: idx,
+			},
+			from.Documents[idx],
+			to.Documents[idx],
+		)
+
+		if err != nil {
+			return Report{}, err
+		}
+
+		result = append(result, diffs...)
+	}
+
+	return Report{From: from, To: to, Diffs: result}, nil
+}
+
+// documentNames returns the Kubernetes resource name of each document, or an
+// empty string for empty documents, and false if a name cannot be determined
+func documentNames(inputFile ytbx.InputFile) ([]string, bool) {
+	names := make([]string, len(inputFile.Documents))
+	for i, document := range inputFile.Documents {
+		if isEmptyDocument(document) {
+			continue
+		}
+
+		name, err := fqrn(document.Content[0])
+		if err != nil {
+			return nil, false
+		}
+
+		names[i] = name
+	}
+
+	return names, true
+}
+
+// subtrees compares the subtrees at the configured root path of all documents
+func (compare *compare) subtrees(from ytbx.InputFile, to ytbx.InputFile) (Report, error) {
+	if len(from.Documents) != len(to.Documents) {
+		return Report{}, fmt.Errorf("comparing YAMLs with a different number of documents is currently not supported")
+	}
+
+	var result []Diff
+	for idx := range from.Documents {
+		fromNode, err := subtree(from.Documents[idx], compare.settings.RootPath)
+		if err != nil {
+			return Report{}, err
+		}
+
+		toNode, err := subtree(to.Documents[idx], compare.settings.RootPath)
+		if err != nil {
+			return Report{}, err
+		}
+
+		path := ytbx.Path{Root: &from, DocumentIdx: idx}
+
+		switch {
+		case fromNode == nil && toNode == nil:
+			continue
+
+		case fromNode == nil:
+			result = append(result, Diff{Path: &path, Details: []Detail{{Kind: ADDITION, To: toNode}}})
+
+		case toNode == nil:
+			result = append(result, Diff{Path: &path, Details: []Detail{{Kind: REMOVAL, From: fromNode}}})
+
+		default:
+			diffs, err := compare.objects(path, fromNode, toNode)
+			if err != nil {
+				return Report{}, err
+			}
+
+			result = append(result, diffs...)
+		}
+	}
+
+	return Report{From: from, To: to, Diffs: result}, nil
+}
+
+// subtree returns the node at the provided path in the document, or nil in
+// case the path cannot be found in the document
+func subtree(document *yamlv3.Node, pathString string) (*yamlv3.Node, error) {
+	if _, err := ytbx.ParsePathStringUnsafe(pathString); err != nil {
+		return nil, err
+	}
+
+	if document == nil || isEmptyDocument(document) {
+		return nil, nil
+	}
+
+	node, err := ytbx.Grab(document, pathString)
+	if err != nil {
+		return nil, nil
+	}
+
+	return node, nil
+}
+
+// preprocess returns a copy of the input file, where all documents were
+// processed using the provided function
+func preprocess(inputFile ytbx.InputFile, side string, fn func(*yamlv3.Node) (*yamlv3.Node, error)) (ytbx.InputFile, error) {
+	documents := make([]*yamlv3.Node, len(inputFile.Documents))
+	for i, document := range inputFile.Documents {
+		result, err := fn(document)
+		if err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to preprocess document #%d of %s input file %s: %w",
+				i+1, side, ytbx.HumanReadableLocationInformation(inputFile), err)
+		}
+
+		documents[i] = result
+	}
+
+	inputFile.Documents = documents
+	return inputFile, nil
+}
+
+// renamePaths returns a copy of the input file, where the values at the old
+// paths of the aliases are moved to the respective new paths. Aliases with an
+// old path that does not exist, or with a new path that already exists or
+// cannot be created, are skipped.
+func renamePaths(inputFile ytbx.InputFile, aliases map[string]string) (ytbx.InputFile, error) {
+	type rename struct{ from, to ytbx.Path }
+
+	oldPaths := make([]string, 0, len(aliases))
+	for oldPath := range aliases {
+		oldPaths = append(oldPaths, oldPath)
+	}
+
+	sort.Strings(oldPaths)
+
+	renames := make([]rename, 0, len(aliases))
+	for _, oldPath := range oldPaths {
+		from, err := ytbx.ParsePathStringUnsafe(oldPath)
+		if err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to parse path alias %s: %w", oldPath, err)
+		}
+
+		to, err := ytbx.ParsePathStringUnsafe(aliases[oldPath])
+		if err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to parse path alias %s: %w", aliases[oldPath], err)
+		}
+
+		if !endsWithMapKey(from) || !endsWithMapKey(to) {
+			return ytbx.InputFile{}, fmt.Errorf("path alias %s → %s does not end in a map key", oldPath, aliases[oldPath])
+		}
+
+		if strings.HasPrefix(to.ToGoPatchStyle()+"/", from.ToGoPatchStyle()+"/") {
+			return ytbx.InputFile{}, fmt.Errorf("path alias %s → %s moves a value into itself", oldPath, aliases[oldPath])
+		}
+
+		renames = append(renames, rename{from: from, to: to})
+	}
+
+	documents := make([]*yamlv3.Node, len(inputFile.Documents))
+	for i, document := range inputFile.Documents {
+		document = copyNode(document)
+		for _, rename := range renames {
+			moveValue(document, rename.from, rename.to)
+		}
+
+		documents[i] = document
+	}
+
+	inputFile.Documents = documents
+	return inputFile, nil
+}
+
+func endsWithMapKey(path ytbx.Path) bool {
+	if len(path.PathElements) == 0 {
+		return false
+	}
+
+	last := path.PathElements[len(path.PathElements)-1]
+	return last.Key == "" && last.Name != ""
+}
+
+// moveValue moves the value at the from path to the to path in the provided
+// document, missing maps on the way to the to path are created
+func moveValue(document *yamlv3.Node, from ytbx.Path, to ytbx.Path) {
+	if document == nil || isEmptyDocument(document) {
+		return
+	}
+
+	root := document
+	if root.Kind == yamlv3.DocumentNode {
+		root = root.Content[0]
+	}
+
+	fromParent := walkPath(root, from.PathElements[:len(from.PathElements)-1], false)
+	if fromParent == nil || fromParent.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	fromKey := from.PathElements[len(from.PathElements)-1].Name
+	keyIdx := -1
+	for i := 0; i+1 < len(fromParent.Content); i += 2 {
+		if followAlias(fromParent.Content[i]).Value == fromKey {
+			keyIdx = i
+			break
+		}
+	}
+
+	if keyIdx < 0 {
+		return
+	}
+
+	if walkPath(root, to.PathElements, false) != nil {
+		return
+	}
+
+	toParent := walkPath(root, to.PathElements[:len(to.PathElements)-1], true)
+	if toParent == nil || toParent.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	value := fromParent.Content[keyIdx+1]
+	fromParent.Content = append(fromParent.Content[:keyIdx:keyIdx], fromParent.Content[keyIdx+2:]...)
+	toParent.Content = append(toParent.Content,
+		&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: to.PathElements[len(to.PathElements)-1].Name},
+		value,
+	)
+}
+
+// walkPath returns the node at the provided path elements, or nil if there is
+// no such node, in case create is set, missing map entries are created as
+// empty maps on the way
+func walkPath(node *yamlv3.Node, elements []ytbx.PathElement, create bool) *yamlv3.Node {
+	for _, element := range elements {
+		node = followAlias(node)
+
+		switch {
+		case element.Key == "" && element.Name != "":
+			if node.Kind != yamlv3.MappingNode {
+				return nil
+			}
+
+			next, ok := findValueByKey(node, element.Name)
+			if !ok {
+				if !create {
+					return nil
+				}
+
+				next = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content,
+					&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: element.Name},
+					next,
+				)
+			}
+
+			node = next
+
+		case element.Key != "":
+			if node.Kind != yamlv3.SequenceNode {
+				return nil
+			}
+
+			next, ok := getEntryFromNamedList(node, ListItemIdentifierField(element.Key), element.Name)
+			if !ok {
+				return nil
+			}
+
+			node = next
+
+		default:
+			if node.Kind != yamlv3.SequenceNode || element.Idx < 0 || element.Idx >= len(node.Content) {
+				return nil
+			}
+
+			node = node.Content[element.Idx]
+		}
+	}
+
+	return node
+}
+
+// copyNode returns a deep copy of the provided node, alias nodes keep pointing
+// to their original anchor node
+func copyNode(node *yamlv3.Node) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	result := *node
+	if node.Content != nil {
+		result.Content = make([]*yamlv3.Node, len(node.Content))
+		for i, content := range node.Content {
+			result.Content[i] = copyNode(content)
+		}
+	}
+
+	return &result
+}
+
+// duplicateKeyWarnings returns a warning for each key, which is used more than
+// once in the same map of any document in the input file
+func duplicateKeyWarnings(side string, inputFile ytbx.InputFile) []string {
+	var warnings []string
+
+	var traverse func(path ytbx.Path, node *yamlv3.Node)
+	traverse = func(path ytbx.Path, node *yamlv3.Node) {
+		switch node.Kind {
+		case yamlv3.DocumentNode:
+			for _, content := range node.Content {
+				traverse(path, content)
+			}
+
+		case yamlv3.SequenceNode:
+			for i, entry := range node.Content {
+				traverse(ytbx.NewPathWithIndexedListElement(path, i), entry)
+			}
+
+		case yamlv3.MappingNode:
+			seen := map[string]struct{}{}
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if _, ok := seen[key]; ok {
+					warnings = append(warnings, fmt.Sprintf("duplicate key %q in map at %s in document #%d of %s input file %s, only one of the values is compared",
+						key, path.String(), path.DocumentIdx+1, side, ytbx.HumanReadableLocationInformation(inputFile)))
+					continue
+				}
+
+				seen[key] = struct{}{}
+				traverse(ytbx.NewPathWithNamedElement(path, key), node.Content[i+1])
+			}
+		}
+	}
+
+	for idx, document := range inputFile.Documents {
+		traverse(ytbx.Path{DocumentIdx: idx}, document)
+	}
+
+	return warnings
+}
+
+// selectDocuments returns a copy of the input file, in which all documents but
+// the ones with the provided indices are replaced with empty documents, so that
+// the selected documents keep their original index in the report
+func selectDocuments(inputFile ytbx.InputFile, indices []int) (ytbx.InputFile, error) {
+	selected := map[int]struct{}{}
+	var last int
+
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(inputFile.Documents) {
+			return ytbx.InputFile{}, fmt.Errorf("document index %d is out of range, %s contains %s",
+				idx,
+				inputFile.Location,
+				text.Plural(len(inputFile.Documents), "document"))
+		}
+
+		selected[idx] = struct{}{}
+		last = max(last, idx)
+	}
+
+	documents := make([]*yamlv3.Node, last+1)
+	for idx := range documents {
+		if _, ok := selected[idx]; ok {
+			documents[idx] = inputFile.Documents[idx]
+			continue
+		}
+
+		documents[idx] = &yamlv3.Node{
+			Kind:    yamlv3.DocumentNode,
+			Content: []*yamlv3.Node{{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}},
+		}
+	}
+
+	inputFile.Documents = documents
+	if len(inputFile.Names) > len(documents) {
+		inputFile.Names = inputFile.Names[:len(documents)]
+	}
+
+	return inputFile, nil
+}
+
+func (compare *compare) objects(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	diffs, err := compare.nodes(path, from, to)
+	if err == nil && compare.stopAtFirstDiff && len(diffs) > 0 {
+		return nil, errDifferenceFound
+	}
+
+	return diffs, err
+}
+
+func (compare *compare) nodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	switch {
+	case compare.isIgnoredPath(path):
+		return []Diff{}, nil
+
+	case from != nil && to != nil && compare.isEqualByCustomComparator(path, from, to):
+		return []Diff{}, nil
+
+	case from == nil && to == nil:
+		return []Diff{}, nil
+
+	case from != nil && to != nil && compare.isTemplatePlaceholder(from):
+		return []Diff{}, nil
+
+	case (from == nil && to != nil) || (from != nil && to == nil):
+		return compare.modification(path, from, to), nil
+
+	case compare.settings.CoerceStringNumbers && isSameStringNumber(from, to):
+		return []Diff{}, nil
+
+	case compare.settings.CanonicalizeNumbers && isSameNumber(from, to):
+		return []Diff{}, nil
+
+	case compare.settings.IgnoreTagOnlyChanges && isTagOnlyChange(from, to):
+		return []Diff{}, nil
+
+	case compare.settings.NormalizeSingletonLists && compare.isSameSingleton(path, from, to):
+		return []Diff{}, nil
+
+	case compare.isIgnoredValueChange(from, to):
+		return []Diff{}, nil
+
+	case compare.isURLPath(path) && isSameURL(from, to):
+		return []Diff{}, nil
+
+	case (from.Kind != to.Kind) || (from.Tag != to.Tag):
+		return compare.modification(path, from, to), nil
+	}
+
+	return compare.nonNilSameKindNodes(path, from, to)
+}
+
+// isTemplatePlaceholder returns whether the node is a scalar with a value that
+// matches the template placeholder pattern
+func (compare *compare) isTemplatePlaceholder(node *yamlv3.Node) bool {
+	return compare.settings.TemplatePlaceholderPattern != nil &&
+		node.Kind == yamlv3.ScalarNode &&
+		compare.settings.TemplatePlaceholderPattern.MatchString(node.Value)
+}
+
+// isIgnoredValueChange returns whether both nodes are scalars with values that
+// match any of the ignored value patterns
+func (compare *compare) isIgnoredValueChange(from *yamlv3.Node, to *yamlv3.Node) bool {
+	if len(compare.settings.IgnoreValuePatterns) == 0 || from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode {
+		return false
+	}
+
+	matches := func(value string) bool {
+		for _, pattern := range compare.settings.IgnoreValuePatterns {
+			if pattern.MatchString(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return matches(from.Value) && matches(to.Value)
+}
+
+// isSameStructure returns whether two maps have the same keys, or two lists
+// have the same number of entries, all other nodes have the same structure.
+// Map keys, which are ignored or only exist on one side with a value that is
+// treated like a missing entry, are not taken into account.
+func (compare *compare) isSameStructure(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) bool {
+	switch from.Kind {
+	case yamlv3.MappingNode:
+		lookup := findValueByKey
+		if compare.isCaseInsensitiveKeyPath(path) {
+			lookup = findValueByKeyFold
+		}
+
+		hasUnmatchedKey := func(node *yamlv3.Node, other *yamlv3.Node) bool {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if _, ok := lookup(other, key.Value); ok {
+					continue
+				}
+
+				if !compare.isIgnorableMissingEntry(value) && !compare.isIgnoredPath(ytbx.NewPathWithNamedElement(path, key.Value)) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		return !hasUnmatchedKey(from, to) && !hasUnmatchedKey(to, from)
+
+	case yamlv3.SequenceNode:
+		return len(from.Content) == len(to.Content)
+	}
+
+	return true
+}
+
+// structuralChange returns the diff for a structural change of the node at the
+// given path, which consists of the removal of the old and the addition of the
+// new node
+func (compare *compare) structuralChange(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) []Diff {
+	diff := Diff{Path: &path, Details: []Detail{}}
+
+	if !compare.isSuppressed(REMOVAL) {
+		diff.Details = append(diff.Details, Detail{Kind: REMOVAL, From: from, To: nil})
+	}
+
+	if !compare.isSuppressed(ADDITION) {
+		diff.Details = append(diff.Details, Detail{Kind: ADDITION, From: nil, To: to})
+	}
+
+	if len(diff.Details) == 0 {
+		return nil
+	}
+
+	return []Diff{diff}
+}
+
+// isSameSingleton returns whether one node is a list with exactly one entry
+// and the other node is not a list, but equal to this one entry
+func (compare *compare) isSameSingleton(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) bool {
+	var list, value *yamlv3.Node
+	switch {
+	case from.Kind == yamlv3.SequenceNode && to.Kind != yamlv3.SequenceNode:
+		list, value = from, to
+
+	case to.Kind == yamlv3.SequenceNode && from.Kind != yamlv3.SequenceNode:
+		list, value = to, from
+
+	default:
+		return false
 	}
 
-	// apply the optional compare options provided to this function call
-	for _, compareOption := range compareOptions {
-		compareOption(&cmpr.settings)
+	if len(list.Content) != 1 {
+		return false
 	}
 
-	// in case Kubernetes mode is enabled, try to compare documents in the YAML
-	// file by their names rather than just by the order of the documents
-	if cmpr.settings.KubernetesEntityDetection {
-		var fromDocs, toDocs []*yamlv3.Node
-		var fromNames, toNames []string
-
-		for i := range from.Documents {
-			if entry := from.Documents[i]; !isEmptyDocument(entry) {
-				fromDocs = append(fromDocs, entry)
-				if name, err := fqrn(entry.Content[0]); err == nil {
-					fromNames = append(fromNames, name)
-				}
-			}
-		}
+	diffs, err := compare.objects(path, followAlias(list.Content[0]), value)
+	return err == nil && len(diffs) == 0
+}
 
-		for i := range to.Documents {
-			if entry := to.Documents[i]; !isEmptyDocument(entry) {
-				toDocs = append(toDocs, entry)
-				if name, err := fqrn(entry.Content[0]); err == nil {
-					toNames = append(toNames, name)
-				}
-			}
-		}
+// isSameStringNumber returns whether one node is a string and the other one is
+// a number, and the string parses to the same numeric value as the number
+func isSameStringNumber(from *yamlv3.Node, to *yamlv3.Node) bool {
+	if from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode {
+		return false
+	}
 
-		// when the look-up of a name for each document in each file worked out, it
-		// means that the documents are most likely Kubernetes resources, so a comparison
-		// using the names can be done, otherwise, leave and continue with default behavior
-		if len(fromNames) == len(fromDocs) && len(toNames) == len(toDocs) {
-			// Reset the docs and names based on the collected details
-			from.Documents, from.Names = fromDocs, fromNames
-			to.Documents, to.Names = toDocs, toNames
+	if to.Tag == "!!str" {
+		from, to = to, from
+	}
 
-			// Compare the document nodes, in case of an error it will fall back to the default
-			// implementation and continue to compare the files without any special semantics
-			if result, err := cmpr.documentNodes(from, to); err == nil {
-				return Report{from, to, result}, nil
-			}
-		}
+	if from.Tag != "!!str" {
+		return false
 	}
 
-	if len(from.Documents) != len(to.Documents) {
-		return Report{}, fmt.Errorf("comparing YAMLs with a different number of documents is currently not supported")
+	str, err := strconv.ParseFloat(from.Value, 64)
+	if err != nil {
+		return false
 	}
 
-	var result []Diff
-	for idx := range from.Documents {
-		diffs, err := cmpr.objects(
-			ytbx.Path{
-				Root:        &from,
-				DocumentIdx: idx,
-			},
-			from.Documents[idx],
-			to.Documents[idx],
-		)
+	number, ok := numericValue(to)
+	return ok && str == number
+}
 
-		if err != nil {
-			return Report{}, err
-		}
+// isTagOnlyChange returns whether both nodes are scalars with the same value
+func isTagOnlyChange(from *yamlv3.Node, to *yamlv3.Node) bool {
+	return from.Kind == yamlv3.ScalarNode &&
+		to.Kind == yamlv3.ScalarNode &&
+		from.Value == to.Value
+}
 
-		result = append(result, diffs...)
+// isSameNumber returns whether both nodes are numbers with the same value
+func isSameNumber(from *yamlv3.Node, to *yamlv3.Node) bool {
+	fromNumber, ok := numericValue(from)
+	if !ok {
+		return false
 	}
 
-	return Report{from, to, result}, nil
+	toNumber, ok := numericValue(to)
+	return ok && fromNumber == toNumber
 }
 
-func (compare *compare) objects(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
-	switch {
-	case from == nil && to == nil:
-		return []Diff{}, nil
+// numericValue returns the value of an integer or float scalar node
+func numericValue(node *yamlv3.Node) (float64, bool) {
+	if node.Kind != yamlv3.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+		return 0, false
+	}
 
-	case (from == nil && to != nil) || (from != nil && to == nil):
-		return []Diff{{
-			&path,
-			[]Detail{{
-				Kind: MODIFICATION,
-				From: from,
-				To:   to,
-			}},
-		}}, nil
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return 0, false
+	}
 
-	case (from.Kind != to.Kind) || (from.Tag != to.Tag):
-		return []Diff{{
-			&path,
-			[]Detail{{
-				Kind: MODIFICATION,
-				From: from,
-				To:   to,
-			}},
-		}}, nil
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+
+	case int64:
+		return float64(number), true
+
+	case uint64:
+		return float64(number), true
+
+	case float64:
+		return number, true
 	}
 
-	return compare.nonNilSameKindNodes(path, from, to)
+	return 0, false
 }
 
 func (compare *compare) nonNilSameKindNodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
 	var diffs []Diff
 	var err error
 
+	if compare.isBeyondMaxDepth(path, from) {
+		if compare.calcNodeHash(from) != compare.calcNodeHash(to) {
+			diffs = compare.modification(path, from, to)
+		}
+
+		return diffs, nil
+	}
+
+	if compare.settings.ScalarOnly && !compare.isSameStructure(path, from, to) {
+		return compare.structuralChange(path, from, to), nil
+	}
+
 	switch from.Kind {
 	case yamlv3.DocumentNode:
 		diffs, err = compare.objects(path, from.Content[0], to.Content[0])
@@ -211,6 +1390,8 @@ func (compare *compare) nonNilSameKindNodes(path ytbx.Path, from *yamlv3.Node, t
 		diffs, err = compare.sequenceNodes(path, from, to)
 
 	case yamlv3.ScalarNode:
+		// Scalars are compared by their resolved value only, the style (quoting)
+		// and comments of a node are not considered to be a difference
 		switch from.Tag {
 		case "!!str":
 			diffs, err = compare.nodeValues(path, from, to)
@@ -220,14 +1401,7 @@ func (compare *compare) nonNilSameKindNodes(path ytbx.Path, from *yamlv3.Node, t
 
 		default:
 			if from.Value != to.Value {
-				diffs, err = []Diff{{
-					&path,
-					[]Detail{{
-						Kind: MODIFICATION,
-						From: from,
-						To:   to,
-					}},
-				}}, nil
+				diffs, err = compare.modification(path, from, to), nil
 			}
 		}
 
@@ -254,6 +1428,10 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 		var names []string
 
 		for i, document := range inputFile.Documents {
+			if isEmptyDocument(document) {
+				continue
+			}
+
 			node := document.Content[0]
 
 			name, err := fqrn(node)
@@ -297,7 +1475,7 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 
 			result = append(result, diffs...)
 
-		} else {
+		} else if !compare.isSuppressed(REMOVAL) {
 			// `from` contain the `key`, but `to` does not -> removal
 			removals = append(removals, fromItem.node)
 		}
@@ -305,7 +1483,7 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 
 	for _, name := range toNames {
 		var toItem = toLookUpMap[name]
-		if _, ok := fromLookUpMap[name]; !ok {
+		if _, ok := fromLookUpMap[name]; !ok && !compare.isSuppressed(ADDITION) {
 			// `to` contains a `key` that `from` does not have -> addition
 			additions = append(additions, toItem.node)
 		}
@@ -339,7 +1517,7 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 		)
 	}
 
-	if !compare.settings.IgnoreOrderChanges && len(fromNames) == len(toNames) {
+	if !compare.isSuppressed(ORDERCHANGE) && len(fromNames) == len(toNames) {
 		for i := range fromNames {
 			if fromNames[i] != toNames[i] {
 				diff.Details = append(diff.Details, Detail{
@@ -356,79 +1534,362 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 		result = append([]Diff{diff}, result...)
 	}
 
-	return result, nil
+	return result, nil
+}
+
+func (compare *compare) mappingNodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	result := make([]Diff, 0)
+	removals := []*yamlv3.Node{}
+	additions := []*yamlv3.Node{}
+
+	lookup := findValueByKey
+	if compare.isCaseInsensitiveKeyPath(path) {
+		lookup = findValueByKeyFold
+	}
+
+	for i := 0; i < len(from.Content); i += 2 {
+		key, fromItem := from.Content[i], from.Content[i+1]
+		if toItem, ok := lookup(to, key.Value); ok {
+			// `from` and `to` contain the same `key` -> require comparison
+			diffs, err := compare.objects(
+				ytbx.NewPathWithNamedElement(path, key.Value),
+				followAlias(fromItem),
+				followAlias(toItem),
+			)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, diffs...)
+
+		} else if !compare.isSuppressed(REMOVAL) && !compare.isIgnorableMissingEntry(fromItem) && !compare.isIgnoredPath(ytbx.NewPathWithNamedElement(path, key.Value)) {
+			// `from` contain the `key`, but `to` does not -> removal
+			removals = append(removals, key, fromItem)
+		}
+	}
+
+	for i := 0; i < len(to.Content); i += 2 {
+		key, toItem := to.Content[i], to.Content[i+1]
+		if _, ok := lookup(from, key.Value); !ok && !compare.isSuppressed(ADDITION) && !compare.isIgnorableMissingEntry(toItem) && !compare.isIgnoredPath(ytbx.NewPathWithNamedElement(path, key.Value)) {
+			// `to` contains a `key` that `from` does not have -> addition
+			additions = append(additions, key, toItem)
+		}
+	}
+
+	diff := Diff{Path: &path, Details: []Detail{}}
+
+	if len(removals) > 0 {
+		diff.Details = append(diff.Details,
+			Detail{
+				Kind: REMOVAL,
+				From: &yamlv3.Node{
+					Kind:    from.Kind,
+					Tag:     from.Tag,
+					Content: removals,
+				},
+				To: nil,
+			},
+		)
+	}
+
+	if len(additions) > 0 {
+		diff.Details = append(diff.Details,
+			Detail{
+				Kind: ADDITION,
+				From: nil,
+				To: &yamlv3.Node{
+					Kind:    to.Kind,
+					Tag:     to.Tag,
+					Content: additions,
+				},
+			},
+		)
+	}
+
+	if len(diff.Details) > 0 {
+		result = append([]Diff{diff}, result...)
+	}
+
+	return result, nil
+}
+
+// isBeyondMaxDepth returns whether the node is a map or list, which is located
+// at the configured maximum depth and must therefore not be compared in detail
+func (compare *compare) isBeyondMaxDepth(path ytbx.Path, node *yamlv3.Node) bool {
+	if compare.settings.MaxCompareDepth <= 0 {
+		return false
+	}
+
+	switch node.Kind {
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return len(path.PathElements) >= compare.settings.MaxCompareDepth
+	}
+
+	return false
+}
+
+// isEqualByCustomComparator returns whether the most specific custom comparator
+// that is registered for the path considers both nodes to be equal
+func (compare *compare) isEqualByCustomComparator(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) bool {
+	if len(compare.settings.Comparators) == 0 {
+		return false
+	}
+
+	var match *pathComparator
+	pathString := path.String()
+	for i, comparator := range compare.settings.Comparators {
+		if ok, err := pathpkg.Match(comparator.pattern, pathString); err != nil || !ok {
+			continue
+		}
+
+		if match == nil || patternSpecificity(comparator.pattern) > patternSpecificity(match.pattern) {
+			match = &compare.settings.Comparators[i]
+		}
+	}
+
+	return match != nil && match.equal(from, to)
+}
+
+// patternSpecificity returns the number of path elements of the pattern, which
+// are no wildcards, so that more specific patterns can take precedence
+func patternSpecificity(pattern string) int {
+	var result int
+	for _, element := range strings.Split(pattern, "/") {
+		if element != "" && !strings.ContainsAny(element, "*?[") {
+			result++
+		}
+	}
+
+	return result
+}
+
+// fieldWeight returns the weight of the most specific pattern that matches
+// the path, or one if no pattern matches
+func fieldWeight(weights map[string]float64, pathString string) float64 {
+	result, specificity := 1.0, -1
+	for pattern, weight := range weights {
+		if ok, err := pathpkg.Match(pattern, pathString); err != nil || !ok {
+			continue
+		}
+
+		if s := patternSpecificity(pattern); s > specificity || (s == specificity && weight > result) {
+			result, specificity = weight, s
+		}
+	}
+
+	return result
+}
+
+// isSuppressed returns whether details of the given kind must not be generated
+func (compare *compare) isSuppressed(kind rune) bool {
+	if kind == ORDERCHANGE && compare.settings.IgnoreOrderChanges {
+		return true
+	}
+
+	if kind == ADDITION && compare.settings.SubsetMode {
+		return true
+	}
+
+	for _, suppressed := range compare.settings.SuppressKinds {
+		if suppressed == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modification returns the diff for a modification of the node at the given
+// path, or nothing in case modifications are suppressed
+func (compare *compare) modification(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) []Diff {
+	if compare.isSuppressed(MODIFICATION) {
+		return nil
+	}
+
+	return []Diff{{
+		Path: &path,
+		Details: []Detail{{
+			Kind: MODIFICATION,
+			From: from,
+			To:   to,
+		}},
+	}}
+}
+
+// isIgnoredPath returns whether the path is configured to be ignored
+func (compare *compare) isIgnoredPath(path ytbx.Path) bool {
+	if compare.isIgnoredAnnotation(path) {
+		return true
+	}
+
+	if len(compare.settings.IgnorePaths) == 0 {
+		return false
+	}
+
+	pathString := path.String()
+	for _, ignorePath := range compare.settings.IgnorePaths {
+		if pathString == ignorePath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIgnoredAnnotation returns whether the path points to an annotation, which
+// key starts with one of the ignored annotation prefixes
+func (compare *compare) isIgnoredAnnotation(path ytbx.Path) bool {
+	elements := path.PathElements
+	if len(compare.settings.IgnoreAnnotationPrefixes) == 0 || len(elements) < 3 {
+		return false
+	}
+
+	parent, grandparent, key := elements[len(elements)-2], elements[len(elements)-3], elements[len(elements)-1]
+	if grandparent.Name != "metadata" || parent.Name != "annotations" || grandparent.Key != "" || parent.Key != "" || key.Key != "" {
+		return false
+	}
+
+	for _, prefix := range compare.settings.IgnoreAnnotationPrefixes {
+		if strings.HasPrefix(key.Name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAppendOnly returns whether the `to` list only has additional entries at its
+// end, that is, every entry of the `from` list is aligned with the entry at the
+// same position in the `to` list by either being equal or having the same name
+func (compare *compare) isAppendOnly(from *yamlv3.Node, to *yamlv3.Node) bool {
+	if len(to.Content) <= len(from.Content) {
+		return false
+	}
+
+	identifier, err := compare.getIdentifierFromNamedLists(from, to)
+	for i, fromEntry := range from.Content {
+		toEntry := to.Content[i]
+		if compare.calcNodeHash(fromEntry) == compare.calcNodeHash(toEntry) {
+			continue
+		}
+
+		if err == nil {
+			fromName, fromErr := nameFromPath(fromEntry, identifier)
+			toName, toErr := nameFromPath(toEntry, identifier)
+			if fromErr == nil && toErr == nil && fromName == toName {
+				continue
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// isMultisetPath returns whether the list at the path is to be compared as a
+// multiset
+func (compare *compare) isMultisetPath(path ytbx.Path) bool {
+	pathString := path.String()
+	for _, multisetPath := range compare.settings.MultisetPaths {
+		if pathString == multisetPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCaseInsensitiveKeyPath returns whether the keys of the map at the path are
+// to be matched case-insensitive
+func (compare *compare) isCaseInsensitiveKeyPath(path ytbx.Path) bool {
+	if len(compare.settings.CaseInsensitiveKeyPaths) == 0 {
+		return false
+	}
+
+	pathString := path.String()
+	for _, keyPath := range compare.settings.CaseInsensitiveKeyPaths {
+		if pathString == keyPath {
+			return true
+		}
+	}
+
+	return false
 }
 
-func (compare *compare) mappingNodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
-	result := make([]Diff, 0)
-	removals := []*yamlv3.Node{}
-	additions := []*yamlv3.Node{}
+// isURLPath returns whether the value at the path is to be compared as a URL
+func (compare *compare) isURLPath(path ytbx.Path) bool {
+	if len(compare.settings.NormalizeURLPaths) == 0 {
+		return false
+	}
 
-	for i := 0; i < len(from.Content); i += 2 {
-		key, fromItem := from.Content[i], from.Content[i+1]
-		if toItem, ok := findValueByKey(to, key.Value); ok {
-			// `from` and `to` contain the same `key` -> require comparison
-			diffs, err := compare.objects(
-				ytbx.NewPathWithNamedElement(path, key.Value),
-				followAlias(fromItem),
-				followAlias(toItem),
-			)
+	pathString := path.String()
+	for _, urlPath := range compare.settings.NormalizeURLPaths {
+		if pathString == urlPath {
+			return true
+		}
+	}
 
-			if err != nil {
-				return nil, err
-			}
+	return false
+}
 
-			result = append(result, diffs...)
+// isSameURL returns whether both nodes are scalars with absolute URLs, which
+// are equal after they were normalized
+func isSameURL(from *yamlv3.Node, to *yamlv3.Node) bool {
+	if from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode {
+		return false
+	}
 
-		} else {
-			// `from` contain the `key`, but `to` does not -> removal
-			removals = append(removals, key, fromItem)
-		}
+	fromURL, ok := normalizeURL(from.Value)
+	if !ok {
+		return false
 	}
 
-	for i := 0; i < len(to.Content); i += 2 {
-		key, toItem := to.Content[i], to.Content[i+1]
-		if _, ok := findValueByKey(from, key.Value); !ok {
-			// `to` contains a `key` that `from` does not have -> addition
-			additions = append(additions, key, toItem)
-		}
+	toURL, ok := normalizeURL(to.Value)
+	if !ok {
+		return false
 	}
 
-	diff := Diff{Path: &path, Details: []Detail{}}
+	return fromURL == toURL
+}
 
-	if len(removals) > 0 {
-		diff.Details = append(diff.Details,
-			Detail{
-				Kind: REMOVAL,
-				From: &yamlv3.Node{
-					Kind:    from.Kind,
-					Tag:     from.Tag,
-					Content: removals,
-				},
-				To: nil,
-			},
-		)
+// normalizeURL returns the URL with lower-case scheme and host, without the
+// default port of the scheme and without a trailing slash in the path
+func normalizeURL(value string) (string, bool) {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", false
 	}
 
-	if len(additions) > 0 {
-		diff.Details = append(diff.Details,
-			Detail{
-				Kind: ADDITION,
-				From: nil,
-				To: &yamlv3.Node{
-					Kind:    to.Kind,
-					Tag:     to.Tag,
-					Content: additions,
-				},
-			},
-		)
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	defaultPorts := map[string]string{"http": "80", "https": "443", "ftp": "21", "ws": "80", "wss": "443"}
+	if port := parsed.Port(); port != "" && port == defaultPorts[parsed.Scheme] {
+		parsed.Host = strings.TrimSuffix(parsed.Host, ":"+port)
 	}
 
-	if len(diff.Details) > 0 {
-		result = append([]Diff{diff}, result...)
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	parsed.RawPath = strings.TrimSuffix(parsed.RawPath, "/")
+
+	return parsed.String(), true
+}
+
+// isIgnorableMissingEntry returns whether the value of a map entry that only
+// exists on one side can be considered equal to the entry not being there
+func (compare *compare) isIgnorableMissingEntry(value *yamlv3.Node) bool {
+	value = followAlias(value)
+
+	switch value.Kind {
+	case yamlv3.ScalarNode:
+		return compare.settings.NullEqualsMissing && value.Tag == "!!null"
+
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return compare.settings.EmptyCollectionsEqualAbsent && len(value.Content) == 0
 	}
 
-	return result, nil
+	return false
 }
 
 func (compare *compare) sequenceNodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
@@ -437,6 +1898,18 @@ func (compare *compare) sequenceNodes(path ytbx.Path, from *yamlv3.Node, to *yam
 		return []Diff{}, nil
 	}
 
+	if compare.settings.IgnoreAppends && compare.isAppendOnly(from, to) {
+		to = &yamlv3.Node{Kind: to.Kind, Tag: to.Tag, Content: to.Content[:len(from.Content)]}
+	}
+
+	if compare.isMultisetPath(path) {
+		return compare.multisetLists(path, from, to)
+	}
+
+	if identifier, ok := compare.mergeKey(path, from, to); ok {
+		return compare.namedEntryLists(path, identifier, from, to)
+	}
+
 	if identifier, err := compare.getIdentifierFromNamedLists(from, to); err == nil {
 		return compare.namedEntryLists(path, identifier, from, to)
 	}
@@ -459,6 +1932,36 @@ func (compare *compare) sequenceNodes(path ytbx.Path, from *yamlv3.Node, to *yam
 	return compare.simpleLists(path, from, to)
 }
 
+// multisetLists compares both lists by the number of occurrences of each entry
+// while ignoring the order of the entries
+func (compare *compare) multisetLists(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	fromLookup := compare.createLookUpMap(from)
+	toLookup := compare.createLookUpMap(to)
+
+	surplus := func(list *yamlv3.Node, lookup map[uint64][]int, other map[uint64][]int) []*yamlv3.Node {
+		result := make([]*yamlv3.Node, 0)
+		seen := map[uint64]struct{}{}
+		for _, entry := range list.Content {
+			hash := compare.calcNodeHash(entry)
+			if _, ok := seen[hash]; ok {
+				continue
+			}
+
+			seen[hash] = struct{}{}
+			for i := len(other[hash]); i < len(lookup[hash]); i++ {
+				result = append(result, entry)
+			}
+		}
+
+		return result
+	}
+
+	return compare.packChangesAndAddToResult([]Diff{}, path, nil,
+		surplus(to, toLookup, fromLookup),
+		surplus(from, fromLookup, toLookup),
+	)
+}
+
 func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
 	removals := make([]*yamlv3.Node, 0)
 	additions := make([]*yamlv3.Node, 0)
@@ -530,11 +2033,131 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 	}
 
 	var orderChanges []Detail
-	if !compare.settings.IgnoreOrderChanges {
+	if !compare.isSuppressed(ORDERCHANGE) {
 		orderChanges = compare.findOrderChangesInSimpleList(fromCommon, toCommon)
 	}
 
-	return packChangesAndAddToResult([]Diff{}, path, orderChanges, additions, removals)
+	result := []Diff{}
+	if compare.settings.ReportPositionChanges {
+		result = append(result, compare.positionChanges(path, "", from, to, orderChanges)...)
+		orderChanges = nil
+	}
+
+	if compare.settings.SequenceMatchThreshold > 0 {
+		similar, remainingAdditions, remainingRemovals, err := compare.similarEntries(path, from, additions, removals)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, similar...)
+		additions, removals = remainingAdditions, remainingRemovals
+	}
+
+	return compare.packChangesAndAddToResult(result, path, orderChanges, additions, removals)
+}
+
+// similarEntries pairs each removed entry with the most similar added entry
+// that meets the configured threshold and compares the pairs with each other,
+// the entries that could not be paired are returned as-is
+func (compare *compare) similarEntries(path ytbx.Path, from *yamlv3.Node, additions, removals []*yamlv3.Node) ([]Diff, []*yamlv3.Node, []*yamlv3.Node, error) {
+	result := []Diff{}
+	unmatched := make([]*yamlv3.Node, 0, len(removals))
+
+	for _, removal := range removals {
+		best, bestScore := -1, compare.settings.SequenceMatchThreshold
+		for i, addition := range additions {
+			if score := similarity(followAlias(removal), followAlias(addition), compare.settings.FieldWeights); score >= bestScore {
+				best, bestScore = i, score
+				if score == 1 {
+					break
+				}
+			}
+		}
+
+		if best < 0 {
+			unmatched = append(unmatched, removal)
+			continue
+		}
+
+		idx := 0
+		for i, entry := range from.Content {
+			if entry == removal {
+				idx = i
+				break
+			}
+		}
+
+		diffs, err := compare.objects(
+			ytbx.NewPathWithIndexedListElement(path, idx),
+			followAlias(removal),
+			followAlias(additions[best]),
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		result = append(result, diffs...)
+		additions = append(additions[:best:best], additions[best+1:]...)
+	}
+
+	return result, additions, unmatched, nil
+}
+
+// similarity returns the weighted share of leaf values that are the same in
+// both nodes in relation to all leaf values of both nodes
+func similarity(from *yamlv3.Node, to *yamlv3.Node, weights map[string]float64) float64 {
+	leaves := func(node *yamlv3.Node) map[string]string {
+		result := map[string]string{}
+
+		var traverse func(prefix string, node *yamlv3.Node)
+		traverse = func(prefix string, node *yamlv3.Node) {
+			node = followAlias(node)
+			switch node.Kind {
+			case yamlv3.MappingNode:
+				for i := 0; i < len(node.Content); i += 2 {
+					traverse(prefix+"/"+node.Content[i].Value, node.Content[i+1])
+				}
+
+			case yamlv3.SequenceNode:
+				for i, entry := range node.Content {
+					traverse(prefix+"/"+strconv.Itoa(i), entry)
+				}
+
+			default:
+				if prefix == "" {
+					prefix = "/"
+				}
+
+				result[prefix] = node.Tag + ":" + node.Value
+			}
+		}
+
+		traverse("", node)
+		return result
+	}
+
+	fromLeaves, toLeaves := leaves(from), leaves(to)
+
+	var same, total float64
+	for key, value := range fromLeaves {
+		weight := fieldWeight(weights, key)
+		total += weight
+		if other, ok := toLeaves[key]; ok && other == value {
+			same += weight
+		}
+	}
+
+	for key := range toLeaves {
+		if _, ok := fromLeaves[key]; !ok {
+			total += fieldWeight(weights, key)
+		}
+	}
+
+	if total == 0 {
+		return 1
+	}
+
+	return same / total
 }
 
 func nameFromPath(node *yamlv3.Node, field ListItemIdentifierField) (string, error) {
@@ -606,24 +2229,76 @@ func (compare *compare) namedEntryLists(path ytbx.Path, identifier ListItemIdent
 	}
 
 	var orderChanges []Detail
-	if !compare.settings.IgnoreOrderChanges {
+	if !compare.isSuppressed(ORDERCHANGE) {
 		orderChanges = findOrderChangesInNamedEntryLists(fromNames, toNames)
 	}
 
-	return packChangesAndAddToResult(result, path, orderChanges, additions, removals)
+	if compare.settings.ReportPositionChanges {
+		result = append(result, compare.positionChanges(path, identifier, from, to, orderChanges)...)
+		orderChanges = nil
+	}
+
+	return compare.packChangesAndAddToResult(result, path, orderChanges, additions, removals)
+}
+
+// positionChanges converts the order change details of a list into one
+// position change per list entry that moved without changing its content,
+// where the values are the indices of the entry in the from and to list.
+// The identifier is empty for lists without identifiers, in which case the
+// order change contains the list entries themselves instead of their names.
+func (compare *compare) positionChanges(path ytbx.Path, identifier ListItemIdentifierField, from *yamlv3.Node, to *yamlv3.Node, orderChanges []Detail) []Diff {
+	indexOf := func(list *yamlv3.Node, entry *yamlv3.Node) int {
+		for i, candidate := range list.Content {
+			if candidate == entry {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	indexNode := func(idx int) *yamlv3.Node {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.Itoa(idx)}
+	}
+
+	var result []Diff
+	for _, orderChange := range orderChanges {
+		for _, move := range orderChange.Moves() {
+			var entryPath ytbx.Path
+			var fromEntry, toEntry *yamlv3.Node
+
+			if identifier == "" {
+				fromEntry, toEntry = move.Value, orderChange.To.Content[move.ToIndex]
+				entryPath = ytbx.NewPathWithIndexedListElement(path, indexOf(from, fromEntry))
+
+			} else {
+				fromEntry, _ = getEntryFromNamedList(from, identifier, move.Value.Value)
+				toEntry, _ = getEntryFromNamedList(to, identifier, move.Value.Value)
+				if compare.calcNodeHash(followAlias(fromEntry)) != compare.calcNodeHash(followAlias(toEntry)) {
+					continue
+				}
+
+				entryPath = ytbx.NewPathWithNamedListElement(path, identifier, move.Value.Value)
+			}
+
+			result = append(result, Diff{
+				Path: &entryPath,
+				Details: []Detail{{
+					Kind: POSITIONCHANGE,
+					From: indexNode(indexOf(from, fromEntry)),
+					To:   indexNode(indexOf(to, toEntry)),
+				}},
+			})
+		}
+	}
+
+	return result
 }
 
 func (compare *compare) nodeValues(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
 	result := make([]Diff, 0)
 	if strings.Compare(from.Value, to.Value) != 0 {
-		result = append(result, Diff{
-			&path,
-			[]Detail{{
-				Kind: MODIFICATION,
-				From: from,
-				To:   to,
-			}},
-		})
+		result = append(result, compare.modification(path, from, to)...)
 	}
 
 	return result, nil
@@ -700,7 +2375,7 @@ func findOrderChangesInNamedEntryLists(fromNames, toNames []string) []Detail {
 	return orderchanges
 }
 
-func packChangesAndAddToResult(list []Diff, path ytbx.Path, orderchanges []Detail, additions, removals []*yamlv3.Node) ([]Diff, error) {
+func (compare *compare) packChangesAndAddToResult(list []Diff, path ytbx.Path, orderchanges []Detail, additions, removals []*yamlv3.Node) ([]Diff, error) {
 	// Prepare a diff for this path to added to the result set (if there are changes)
 	diff := Diff{Path: &path, Details: []Detail{}}
 
@@ -708,7 +2383,7 @@ func packChangesAndAddToResult(list []Diff, path ytbx.Path, orderchanges []Detai
 		diff.Details = append(diff.Details, orderchanges...)
 	}
 
-	if len(removals) > 0 {
+	if len(removals) > 0 && !compare.isSuppressed(REMOVAL) {
 		diff.Details = append(diff.Details, Detail{
 			Kind: REMOVAL,
 			From: &yamlv3.Node{
@@ -720,7 +2395,7 @@ func packChangesAndAddToResult(list []Diff, path ytbx.Path, orderchanges []Detai
 		})
 	}
 
-	if len(additions) > 0 {
+	if len(additions) > 0 && !compare.isSuppressed(ADDITION) {
 		diff.Details = append(diff.Details, Detail{
 			Kind: ADDITION,
 			From: nil,
@@ -760,6 +2435,19 @@ func findValueByKey(mappingNode *yamlv3.Node, key string) (*yamlv3.Node, bool) {
 	return nil, false
 }
 
+// findValueByKeyFold returns the value for a given key in a provided mapping
+// node like findValueByKey, but matches the key case-insensitive
+func findValueByKeyFold(mappingNode *yamlv3.Node, key string) (*yamlv3.Node, bool) {
+	for i := 0; i < len(mappingNode.Content); i += 2 {
+		k, v := followAlias(mappingNode.Content[i]), followAlias(mappingNode.Content[i+1])
+		if strings.EqualFold(k.Value, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
 // getValueByKey returns the value for a given key in a provided mapping node,
 // or nil with an error if there is no such entry. This is comparable to getting
 // a value from a map with `foobar[key]`.
@@ -855,6 +2543,53 @@ func (compare *compare) getIdentifierFromNamedLists(listA, listB *yamlv3.Node) (
 	return "", fmt.Errorf("unable to find a key that can serve as an unique identifier")
 }
 
+// mergeKey returns the merge key of the most specific pattern that matches the
+// list at the given path, in case all entries of both lists can be uniquely
+// identified by it
+func (compare *compare) mergeKey(path ytbx.Path, from, to *yamlv3.Node) (ListItemIdentifierField, bool) {
+	isUniqueIn := func(list *yamlv3.Node, identifier ListItemIdentifierField) bool {
+		names := map[string]struct{}{}
+		for _, entry := range list.Content {
+			name, err := nameFromPath(followAlias(entry), identifier)
+			if err != nil {
+				return false
+			}
+
+			if _, found := names[name]; found {
+				return false
+			}
+
+			names[name] = struct{}{}
+		}
+
+		return true
+	}
+
+	var (
+		pathString  = path.String()
+		result      ListItemIdentifierField
+		best        string
+		specificity = -1
+	)
+
+	for pattern, key := range compare.settings.MergeKeys {
+		if ok, err := pathpkg.Match(pattern, pathString); err != nil || !ok {
+			continue
+		}
+
+		if s := patternSpecificity(pattern); s < specificity || (s == specificity && pattern > best) {
+			continue
+		}
+
+		identifier := ListItemIdentifierField(key)
+		if isUniqueIn(from, identifier) && isUniqueIn(to, identifier) {
+			result, best, specificity = identifier, pattern, patternSpecificity(pattern)
+		}
+	}
+
+	return result, specificity >= 0
+}
+
 // getIdentifierFromKubernetesEntityList returns 'metadata.name' as a field identifier if the provided objects all have the key.
 func getIdentifierFromKubernetesEntityList(listA, listB *yamlv3.Node) (ListItemIdentifierField, error) {
 	key := ListItemIdentifierField("metadata.name")
@@ -1132,7 +2867,7 @@ func ChangeRoot(inputFile *ytbx.InputFile, path string, useGoPatchPaths bool, tr
 
 	// Parse path string and create nicely formatted output path
 	if resolvedPath, err := ytbx.ParsePathString(path, originalRoot); err == nil {
-		path = pathToString(&resolvedPath, useGoPatchPaths, multipleDocuments)
+		path = pathToString(&resolvedPath, pathStyleOf(useGoPatchPaths, false), "", multipleDocuments)
 	}
 
 	inputFile.Note = fmt.Sprintf("YAML root was changed to %s", path)
@@ -1140,14 +2875,140 @@ func ChangeRoot(inputFile *ytbx.InputFile, path string, useGoPatchPaths bool, tr
 	return nil
 }
 
-func pathToString(path *ytbx.Path, useGoPatchPaths bool, showPathRoot bool) string {
+// PathStyle defines the notation that is used to render paths
+type PathStyle int
+
+// Supported path styles
+const (
+	DotStylePaths PathStyle = iota
+	GoPatchStylePaths
+	JSONPathStylePaths
+)
+
+// pathStyleOf returns the path style based on the report writer flags, where
+// JSONPath takes precedence over Go-Patch style
+func pathStyleOf(useGoPatchPaths bool, useJSONPaths bool) PathStyle {
+	switch {
+	case useJSONPaths:
+		return JSONPathStylePaths
+
+	case useGoPatchPaths:
+		return GoPatchStylePaths
+	}
+
+	return DotStylePaths
+}
+
+// PathString returns the plain text representation of the path in the
+// provided style, or an empty string if there is no path. Dot-style paths use
+// the default `.` separator, see PathStringWithSeparator for other separators.
+func PathString(path *ytbx.Path, style PathStyle) string {
+	return PathStringWithSeparator(path, style, "")
+}
+
+// PathStringWithSeparator returns the plain text representation of the path
+// like PathString, but uses the provided separator between the elements of a
+// dot-style path, for example `/` or `::`. With a separator other than the
+// default `.` (which is also used for an empty separator), occurrences of the
+// separator in keys and names are escaped with a backslash, as well as the
+// backslash itself. Go-Patch and JSONPath style paths are not affected.
+func PathStringWithSeparator(path *ytbx.Path, style PathStyle, separator string) string {
+	if path == nil {
+		return ""
+	}
+
+	switch style {
+	case GoPatchStylePaths:
+		return path.ToGoPatchStyle()
+
+	case JSONPathStylePaths:
+		return jsonPathString(path)
+	}
+
+	if separator == "" || separator == "." {
+		return path.ToDotStyle()
+	}
+
+	sections := []string{}
+	for _, element := range path.PathElements {
+		switch {
+		case element.Name != "":
+			sections = append(sections, escapePathElement(element.Name, separator))
+
+		case element.Idx >= 0:
+			sections = append(sections, strconv.Itoa(element.Idx))
+		}
+	}
+
+	return strings.Join(sections, separator)
+}
+
+// escapePathElement escapes the separator and the backslash in a key or name
+// of a dot-style path, unless the default separator is used
+func escapePathElement(text string, separator string) string {
+	if separator == "" || separator == "." {
+		return text
+	}
+
+	return strings.NewReplacer(`\`, `\\`, separator, `\`+separator).Replace(text)
+}
+
+var jsonPathIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPathString renders the path in JSONPath notation, for example
+// `$.spec.containers[0].image`, where keys with special characters are quoted
+// in brackets and named list entries are selected using a filter expression
+func jsonPathString(path *ytbx.Path) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+
+	quote := func(text string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text) + "'"
+	}
+
+	for _, element := range path.PathElements {
+		switch {
+		case element.Key == "" && element.Name != "":
+			if jsonPathIdentifier.MatchString(element.Name) {
+				sb.WriteString("." + element.Name)
+			} else {
+				sb.WriteString("[" + quote(element.Name) + "]")
+			}
+
+		case element.Key != "" && element.Name != "":
+			sb.WriteString("[?(@." + element.Key + "==" + quote(element.Name) + ")]")
+
+		default:
+			sb.WriteString(fmt.Sprintf("[%d]", element.Idx))
+		}
+	}
+
+	return sb.String()
+}
+
+// showPathRoot returns whether the document of the path is shown, which is only
+// the case if there is more than one document to show. Paths of merged reports,
+// for example of directories, refer to the input file they belong to as root.
+func showPathRoot(from ytbx.InputFile, path *ytbx.Path) bool {
+	if path != nil && path.Root != nil && len(path.Root.Documents) > 1 {
+		return true
+	}
+
+	return len(from.Documents) > 1
+}
+
+func pathToString(path *ytbx.Path, style PathStyle, separator string, showPathRoot bool) string {
 	var result string
 
-	if useGoPatchPaths {
+	switch style {
+	case GoPatchStylePaths:
 		result = styledGoPatchPath(path)
 
-	} else {
-		result = styledDotStylePath(path)
+	case JSONPathStylePaths:
+		result = styledJSONPath(path)
+
+	default:
+		result = styledDotStylePath(path, separator)
 	}
 
 	if path != nil && showPathRoot {
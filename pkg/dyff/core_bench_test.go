@@ -0,0 +1,118 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+func modifiedValuesInputFiles(b *testing.B, size int) (ytbx.InputFile, ytbx.InputFile) {
+	var from, to strings.Builder
+	for i := 0; i < size; i++ {
+		fmt.Fprintf(&from, "key-%d: {a: %d, b: %d, c: %d, d: %d, e: %d}\n", i, i, i, i, i, i)
+		fmt.Fprintf(&to, "key-%d: {a: %d, b: %d, c: %d, d: %d, e: %d}\n", i, i+1, i+1, i+1, i+1, i+1)
+	}
+
+	load := func(data string) ytbx.InputFile {
+		var node yamlv3.Node
+		if err := yamlv3.Unmarshal([]byte(data), &node); err != nil {
+			b.Fatal(err)
+		}
+
+		return ytbx.InputFile{Documents: []*yamlv3.Node{&node}}
+	}
+
+	return load(from.String()), load(to.String())
+}
+
+func BenchmarkCompareModifiedValues(b *testing.B) {
+	from, to := modifiedValuesInputFiles(b, 500)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dyff.CompareInputFiles(from, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompareModifiedValuesWithSuppressedModifications(b *testing.B) {
+	from, to := modifiedValuesInputFiles(b, 500)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dyff.CompareInputFiles(from, to, dyff.SuppressKinds(dyff.MODIFICATION)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func differingInputFiles(b *testing.B, size int) (ytbx.InputFile, ytbx.InputFile) {
+	var from, to strings.Builder
+	for i := 0; i < size; i++ {
+		fmt.Fprintf(&from, "key-%d: {value: %d, list: [a, b, c]}\n", i, i)
+		fmt.Fprintf(&to, "key-%d: {value: %d, list: [c, b, a]}\n", i, i+1)
+	}
+
+	load := func(data string) ytbx.InputFile {
+		var node yamlv3.Node
+		if err := yamlv3.Unmarshal([]byte(data), &node); err != nil {
+			b.Fatal(err)
+		}
+
+		return ytbx.InputFile{Documents: []*yamlv3.Node{&node}}
+	}
+
+	return load(from.String()), load(to.String())
+}
+
+func BenchmarkCompareDifferingInputs(b *testing.B) {
+	from, to := differingInputFiles(b, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dyff.CompareInputFiles(from, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEqualDifferingInputs(b *testing.B) {
+	from, to := differingInputFiles(b, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if equal, err := dyff.Equal(from, to); err != nil || equal {
+			b.Fatal(equal, err)
+		}
+	}
+}
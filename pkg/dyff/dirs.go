@@ -0,0 +1,154 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// CompareDirs compares all files of two directories, where files are matched
+// by their path relative to the directory. Files that only exist in one of the
+// directories are reported as the removal or addition of all of their
+// documents. Each difference of the returned report has the relative path of
+// the file set as its source.
+func CompareDirs(fromDir string, toDir string, compareOptions ...CompareOption) (Report, error) {
+	cmpr := newCompare(compareOptions...)
+
+	fromFiles, err := listFiles(fromDir)
+	if err != nil {
+		return Report{}, err
+	}
+
+	toFiles, err := listFiles(toDir)
+	if err != nil {
+		return Report{}, err
+	}
+
+	load := func(dir string, name string) (ytbx.InputFile, error) {
+		location := filepath.Join(dir, filepath.FromSlash(name))
+
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return ytbx.InputFile{}, err
+		}
+
+		documents, err := loadDocuments(data, cmpr.settings.Format)
+		if err != nil {
+			return ytbx.InputFile{}, fmt.Errorf("failed to load %s: %w", location, err)
+		}
+
+		return ytbx.InputFile{Location: location, Documents: documents}, nil
+	}
+
+	reports := map[string]Report{}
+	for name := range fromFiles {
+		from, err := load(fromDir, name)
+		if err != nil {
+			return Report{}, err
+		}
+
+		if _, ok := toFiles[name]; !ok {
+			reports[name] = Report{From: from, Diffs: []Diff{{
+				Details: []Detail{{Kind: REMOVAL, From: documentsNode(from), To: nil}},
+			}}}
+
+			continue
+		}
+
+		to, err := load(toDir, name)
+		if err != nil {
+			return Report{}, err
+		}
+
+		if reports[name], err = CompareInputFiles(from, to, compareOptions...); err != nil {
+			return Report{}, fmt.Errorf("failed to compare %s: %w", name, err)
+		}
+	}
+
+	for name := range toFiles {
+		if _, ok := fromFiles[name]; ok {
+			continue
+		}
+
+		to, err := load(toDir, name)
+		if err != nil {
+			return Report{}, err
+		}
+
+		reports[name] = Report{To: to, Diffs: []Diff{{
+			Details: []Detail{{Kind: ADDITION, From: nil, To: documentsNode(to)}},
+		}}}
+	}
+
+	report := MergeReportsWithSource(reports)
+	report.From = ytbx.InputFile{Location: fromDir}
+	report.To = ytbx.InputFile{Location: toDir}
+
+	return report, nil
+}
+
+// listFiles returns the paths (slash separated and relative to the directory)
+// of all regular files in the directory and its sub-directories
+func listFiles(dir string) (map[string]struct{}, error) {
+	files := map[string]struct{}{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = struct{}{}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+// documentsNode returns a node with all non-empty documents of the input file,
+// the same way as whole documents are reported as added or removed
+func documentsNode(inputFile ytbx.InputFile) *yamlv3.Node {
+	node := &yamlv3.Node{Kind: yamlv3.DocumentNode}
+	for _, document := range inputFile.Documents {
+		if !isEmptyDocument(document) {
+			node.Content = append(node.Content, document.Content[0])
+		}
+	}
+
+	return node
+}
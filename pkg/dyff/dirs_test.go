@@ -0,0 +1,123 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("comparing directories", func() {
+	var fromDir, toDir string
+
+	writeFiles := func(dir string, files map[string]string) {
+		for name, content := range files {
+			location := filepath.Join(dir, filepath.FromSlash(name))
+			Expect(os.MkdirAll(filepath.Dir(location), 0o755)).To(Succeed())
+			Expect(os.WriteFile(location, []byte(content), 0o644)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		fromDir, err = os.MkdirTemp("", "dyff-from-")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(os.RemoveAll, fromDir)
+
+		toDir, err = os.MkdirTemp("", "dyff-to-")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(os.RemoveAll, toDir)
+
+		writeFiles(fromDir, map[string]string{
+			"app/config.yml":  "name: app\nreplicas: 1\n",
+			"app/same.yml":    "foo: bar\n",
+			"legacy/old.yml":  "obsolete: true\n",
+			"service/svc.yml": "port: 80\n",
+		})
+
+		writeFiles(toDir, map[string]string{
+			"app/config.yml":  "name: app\nreplicas: 3\n",
+			"app/same.yml":    "foo: bar\n",
+			"service/svc.yml": "port: 80\n",
+			"service/new.yml": "created: true\n",
+		})
+	})
+
+	Context("comparing two directory trees", func() {
+		It("should report changed, removed, and added files by their relative path", func() {
+			report, err := dyff.CompareDirs(fromDir, toDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).To(HaveLen(3))
+
+			Expect(report.Diffs[0].Source).To(Equal("app/config.yml"))
+			Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/replicas", dyff.MODIFICATION, 1, 3)))
+
+			Expect(report.Diffs[1].Source).To(Equal("legacy/old.yml"))
+			Expect(report.Diffs[1].Path).To(BeNil())
+			Expect(report.Diffs[1].Details).To(HaveLen(1))
+			Expect(report.Diffs[1].Details[0].Kind).To(Equal(dyff.REMOVAL))
+
+			Expect(report.Diffs[2].Source).To(Equal("service/new.yml"))
+			Expect(report.Diffs[2].Path).To(BeNil())
+			Expect(report.Diffs[2].Details).To(HaveLen(1))
+			Expect(report.Diffs[2].Details[0].Kind).To(Equal(dyff.ADDITION))
+		})
+
+		It("should prefix the paths with the relative file name in the human report", func() {
+			report, err := dyff.CompareDirs(fromDir, toDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.HumanReport{Report: report, OmitHeader: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("app/config.yml: replicas\n"))
+			Expect(buf.String()).To(ContainSubstring("legacy/old.yml: (file level)\n"))
+		})
+
+		It("should show the document of a file with multiple documents in the human report", func() {
+			writeFiles(fromDir, map[string]string{
+				"k8s/configmaps.yml": "---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: x, namespace: default}\ndata: {a: foo}\n---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: y, namespace: default}\ndata: {a: foo}\n",
+			})
+
+			writeFiles(toDir, map[string]string{
+				"k8s/configmaps.yml": "---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: x, namespace: default}\ndata: {a: foo}\n---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: y, namespace: default}\ndata: {a: bar}\n",
+			})
+
+			report, err := dyff.CompareDirs(fromDir, toDir, dyff.KubernetesEntityDetection(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.HumanReport{Report: report, OmitHeader: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("k8s/configmaps.yml: data.a  (ConfigMap/default/y)\n"))
+			Expect(buf.String()).To(ContainSubstring("app/config.yml: replicas\n"))
+		})
+
+		It("should fail for a directory that does not exist", func() {
+			_, err := dyff.CompareDirs(filepath.Join(fromDir, "does-not-exist"), toDir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,269 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// InputFormat defines the format that is used to parse raw input data
+type InputFormat string
+
+// Supported input formats, an empty input format means that the format is
+// detected automatically (YAML, JSON, or TOML)
+const (
+	FormatAuto       InputFormat = ""
+	FormatYAML       InputFormat = "yaml"
+	FormatJSON       InputFormat = "json"
+	FormatTOML       InputFormat = "toml"
+	FormatHCL        InputFormat = "hcl"
+	FormatProperties InputFormat = "properties"
+	FormatDotEnv     InputFormat = "dotenv"
+	FormatINI        InputFormat = "ini"
+)
+
+// Format specifies the input format that is used to parse raw input data
+func Format(format InputFormat) CompareOption {
+	return func(settings *compareSettings) {
+		settings.Format = format
+	}
+}
+
+func loadDocuments(input []byte, format InputFormat) ([]*yamlv3.Node, error) {
+	input, err := decompress(input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatAuto:
+		return ytbx.LoadDocuments(input)
+
+	case FormatYAML:
+		return ytbx.LoadYAMLDocuments(input)
+
+	case FormatJSON:
+		return ytbx.LoadJSONDocuments(input)
+
+	case FormatTOML:
+		return ytbx.LoadTOMLDocuments(input)
+
+	case FormatHCL:
+		return loadHCLDocuments(input)
+
+	case FormatProperties:
+		return loadKeyValueDocuments(input, parsePropertiesLine)
+
+	case FormatDotEnv:
+		return loadKeyValueDocuments(input, parseDotEnvLine)
+
+	case FormatINI:
+		return loadINIDocuments(input)
+	}
+
+	return nil, fmt.Errorf("unsupported input format %q", format)
+}
+
+// decompress inflates gzip compressed input data, which is detected by its
+// magic bytes, all other input data is returned unchanged
+func decompress(input []byte) ([]byte, error) {
+	if !bytes.HasPrefix(input, []byte{0x1f, 0x8b}) {
+		return input, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress gzip input: %w", err)
+	}
+	defer reader.Close()
+
+	result, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress gzip input: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadKeyValueDocuments parses key/value lines into one document with a flat
+// mapping, ignoring blank lines. In case a key is defined more than once, the
+// last definition wins.
+func loadKeyValueDocuments(input []byte, parseLine func(string) (string, string, bool, error)) ([]*yamlv3.Node, error) {
+	mapping := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		key, value, ok, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse line %d: %w", lineNumber, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if existing, found := findValueByKey(mapping, key); found {
+			existing.Value = value
+			continue
+		}
+
+		mapping.Content = append(mapping.Content,
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key},
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value},
+		)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return []*yamlv3.Node{{
+		Kind:    yamlv3.DocumentNode,
+		Content: []*yamlv3.Node{mapping},
+	}}, nil
+}
+
+// iniDefaultSection is the name of the section for keys that are defined
+// before the first section header of an INI file
+const iniDefaultSection = "default"
+
+// loadINIDocuments parses an INI file into one document with a mapping of the
+// sections, where each section is a flat mapping of its keys and values. In
+// case a key is defined more than once in a section, the last definition wins.
+func loadINIDocuments(input []byte) ([]*yamlv3.Node, error) {
+	root := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+
+	section := func(name string) *yamlv3.Node {
+		if existing, found := findValueByKey(root, name); found {
+			return existing
+		}
+
+		mapping := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content,
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: name},
+			mapping,
+		)
+
+		return mapping
+	}
+
+	var current *yamlv3.Node
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("failed to parse line %d: unterminated section header %q", lineNumber, line)
+			}
+
+			current = section(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return nil, fmt.Errorf("failed to parse line %d: expected key=value, but got %q", lineNumber, line)
+		}
+
+		key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		if current == nil {
+			current = section(iniDefaultSection)
+		}
+
+		if existing, found := findValueByKey(current, key); found {
+			existing.Value = value
+			continue
+		}
+
+		current.Content = append(current.Content,
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key},
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value},
+		)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return []*yamlv3.Node{{
+		Kind:    yamlv3.DocumentNode,
+		Content: []*yamlv3.Node{root},
+	}}, nil
+}
+
+// parsePropertiesLine parses a line of a Java style properties file, where the
+// key and value are separated by either an equal sign or a colon
+func parsePropertiesLine(line string) (string, string, bool, error) {
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return "", "", false, nil
+	}
+
+	idx := strings.IndexAny(line, "=:")
+	if idx < 0 {
+		return line, "", true, nil
+	}
+
+	return strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:]), true, nil
+}
+
+// parseDotEnvLine parses a line of a dotenv file, which has to be of the form
+// `KEY=value` with an optional `export` prefix and optionally quoted value
+func parseDotEnvLine(line string) (string, string, bool, error) {
+	if strings.HasPrefix(line, "#") {
+		return "", "", false, nil
+	}
+
+	line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+	idx := strings.Index(line, "=")
+	if idx <= 0 {
+		return "", "", false, fmt.Errorf("expected KEY=value, but got %q", line)
+	}
+
+	key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return key, value, true, nil
+}
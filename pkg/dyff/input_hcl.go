@@ -0,0 +1,230 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// loadHCLDocuments parses input in the HashiCorp configuration language (HCL)
+// native syntax. Blocks are mapped to nested maps keyed by block type and
+// labels, repeated blocks of the same type and labels become a list.
+// Expressions other than literal values (e.g. references or function calls)
+// are kept as strings.
+func loadHCLDocuments(input []byte) ([]*yamlv3.Node, error) {
+	file, diags := hclsyntax.ParseConfig(input, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, hclError(diags)
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse HCL: unexpected body type %T", file.Body)
+	}
+
+	return []*yamlv3.Node{{
+		Kind:    yamlv3.DocumentNode,
+		Content: []*yamlv3.Node{hclBody(body, input)},
+	}}, nil
+}
+
+func hclError(diags hcl.Diagnostics) error {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+
+		if diag.Subject != nil {
+			return fmt.Errorf("failed to parse HCL in line %d: %s: %s", diag.Subject.Start.Line, diag.Summary, diag.Detail)
+		}
+
+		return fmt.Errorf("failed to parse HCL: %s: %s", diag.Summary, diag.Detail)
+	}
+
+	return fmt.Errorf("failed to parse HCL: %w", diags)
+}
+
+// hclBody converts the attributes and blocks of a body into a mapping, in the
+// order in which they appear in the input
+func hclBody(body *hclsyntax.Body, input []byte) *yamlv3.Node {
+	mapping := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+
+	attributes := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attribute := range body.Attributes {
+		attributes = append(attributes, attribute)
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].SrcRange.Start.Byte < attributes[j].SrcRange.Start.Byte
+	})
+
+	blocks := body.Blocks
+	for len(attributes) > 0 || len(blocks) > 0 {
+		if len(blocks) == 0 || (len(attributes) > 0 && attributes[0].SrcRange.Start.Byte < blocks[0].TypeRange.Start.Byte) {
+			mapping.Content = append(mapping.Content,
+				hclString(attributes[0].Name),
+				hclExpression(attributes[0].Expr, input),
+			)
+
+			attributes = attributes[1:]
+			continue
+		}
+
+		block := blocks[0]
+		addHCLBlock(mapping, append([]string{block.Type}, block.Labels...), hclBody(block.Body, input))
+		blocks = blocks[1:]
+	}
+
+	return mapping
+}
+
+// addHCLBlock adds the block content to the mapping using nested maps for the
+// block type and labels, repeated blocks with the same keys become a list
+func addHCLBlock(mapping *yamlv3.Node, keys []string, content *yamlv3.Node) {
+	for _, key := range keys[:len(keys)-1] {
+		next, found := findValueByKey(mapping, key)
+		if !found || next.Kind != yamlv3.MappingNode {
+			next = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, hclString(key), next)
+		}
+
+		mapping = next
+	}
+
+	key := keys[len(keys)-1]
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+
+		switch existing := mapping.Content[i+1]; existing.Kind {
+		case yamlv3.SequenceNode:
+			existing.Content = append(existing.Content, content)
+
+		default:
+			mapping.Content[i+1] = &yamlv3.Node{
+				Kind:    yamlv3.SequenceNode,
+				Tag:     "!!seq",
+				Content: []*yamlv3.Node{existing, content},
+			}
+		}
+
+		return
+	}
+
+	mapping.Content = append(mapping.Content, hclString(key), content)
+}
+
+// hclExpression converts literal values, lists, and objects into the
+// respective nodes, all other expressions are kept as-is in a string
+func hclExpression(expr hclsyntax.Expression, input []byte) *yamlv3.Node {
+	switch expr := expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		sequence := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		for _, entry := range expr.Exprs {
+			sequence.Content = append(sequence.Content, hclExpression(entry, input))
+		}
+
+		return sequence
+
+	case *hclsyntax.ObjectConsExpr:
+		mapping := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		for _, item := range expr.Items {
+			mapping.Content = append(mapping.Content,
+				hclString(hclObjectKey(item.KeyExpr, input)),
+				hclExpression(item.ValueExpr, input),
+			)
+		}
+
+		return mapping
+
+	case *hclsyntax.TemplateExpr:
+		return hclString(hclTemplate(expr, input))
+
+	case *hclsyntax.TemplateWrapExpr:
+		return hclString("${" + hclSource(expr.Wrapped, input) + "}")
+	}
+
+	raw := hclSource(expr, input)
+	switch {
+	case raw == "true" || raw == "false":
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!bool", Value: raw}
+
+	case raw == "null":
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: raw}
+	}
+
+	if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: raw}
+	}
+
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!float", Value: raw}
+	}
+
+	return hclString(raw)
+}
+
+// hclObjectKey returns the name of an object key, which is either a bare
+// identifier, a literal string, or an arbitrary expression kept as-is
+func hclObjectKey(expr hclsyntax.Expression, input []byte) string {
+	if value, diags := expr.Value(nil); !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+		return value.AsString()
+	}
+
+	return hclSource(expr, input)
+}
+
+// hclTemplate returns the content of a quoted string or heredoc, where template
+// interpolations (`${...}`) are kept as-is
+func hclTemplate(expr *hclsyntax.TemplateExpr, input []byte) string {
+	if value, diags := expr.Value(nil); !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+		return value.AsString()
+	}
+
+	var sb strings.Builder
+	for _, part := range expr.Parts {
+		if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+			sb.WriteString(literal.Val.AsString())
+			continue
+		}
+
+		sb.WriteString("${" + hclSource(part, input) + "}")
+	}
+
+	return sb.String()
+}
+
+func hclSource(expr hclsyntax.Expression, input []byte) string {
+	return strings.TrimSpace(string(expr.Range().SliceBytes(input)))
+}
+
+func hclString(value string) *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value}
+}
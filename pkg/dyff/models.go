@@ -33,6 +33,11 @@ const (
 	REMOVAL      = '-'
 	MODIFICATION = '±'
 	ORDERCHANGE  = '⇆'
+
+	// POSITIONCHANGE is only used with the ReportPositionChanges option and
+	// describes that an unchanged list entry moved from one index to another
+	POSITIONCHANGE = '↕'
+
 	// ILLEGAL      = '✕'
 	// ATTENTION    = '⚠'
 )
@@ -45,18 +50,38 @@ type Detail struct {
 	Kind rune
 }
 
+// Move describes that an entry of a list moved from one index to another
+type Move struct {
+	Value     *yamlv3.Node
+	FromIndex int
+	ToIndex   int
+}
+
 // Diff encapsulates everything noteworthy about a difference
 type Diff struct {
 	Path    *ytbx.Path
 	Details []Detail
+	Note    string
+	Source  string
+}
+
+// ChangeRecord is a flat representation of one detail of a difference, which
+// uses plain Go types for the values (see Report.Records)
+type ChangeRecord struct {
+	Path string
+	Kind string
+	From interface{}
+	To   interface{}
 }
 
 // Report encapsulates the actual end-result of the comparison: The input data
-// and the list of differences
+// and the list of differences, as well as warnings about conditions that may
+// render the comparison incomplete
 type Report struct {
-	From  ytbx.InputFile
-	To    ytbx.InputFile
-	Diffs []Diff
+	From     ytbx.InputFile
+	To       ytbx.InputFile
+	Diffs    []Diff
+	Warnings []string
 }
 
 // ReportWriter defines the interface required for types that can write reports
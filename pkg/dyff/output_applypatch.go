@@ -0,0 +1,334 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"io"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ApplyPatchReport is a reporter that writes the desired delta to get from the
+// `from` to the `to` documents as YAML, which is suitable to be used with a
+// Kubernetes server-side apply. Only changed fields are included, removed
+// fields are explicitly set to null, and lists that cannot be merged by a name
+// are replaced as a whole.
+type ApplyPatchReport struct {
+	Report
+	RedactPaths []string
+}
+
+type applyPatch struct {
+	report *ApplyPatchReport
+	docs   map[int]*yamlv3.Node
+	owned  map[*yamlv3.Node]struct{}
+}
+
+// WriteReport writes the delta documents to the provided writer
+func (report *ApplyPatchReport) WriteReport(out io.Writer) error {
+	patch := applyPatch{
+		report: report,
+		docs:   map[int]*yamlv3.Node{},
+		owned:  map[*yamlv3.Node]struct{}{},
+	}
+
+	var added []*yamlv3.Node
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+		if diff.Path == nil {
+			// Documents that were added are part of the patch as a whole, whereas
+			// removed documents cannot be expressed by an apply patch
+			for _, detail := range diff.Details {
+				if detail.Kind == ADDITION && detail.To != nil {
+					added = append(added, detail.To.Content...)
+				}
+			}
+
+			continue
+		}
+
+		for _, detail := range diff.Details {
+			patch.apply(*diff.Path, detail)
+		}
+	}
+
+	encoder := yamlv3.NewEncoder(out)
+	encoder.SetIndent(2)
+
+	for idx := range report.From.Documents {
+		if doc, ok := patch.docs[idx]; ok {
+			if err := encoder.Encode(doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, doc := range added {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
+
+func (patch *applyPatch) apply(path ytbx.Path, detail Detail) {
+	elements := path.PathElements
+
+	// Entries of lists without names can only be replaced as a whole list
+	for i, element := range elements {
+		if element.Idx >= 0 {
+			patch.replaceList(path.DocumentIdx, elements[:i])
+			return
+		}
+	}
+
+	switch detail.Kind {
+	case MODIFICATION:
+		if len(elements) == 0 {
+			patch.docs[path.DocumentIdx] = detail.To
+			return
+		}
+
+		patch.set(path.DocumentIdx, elements[:len(elements)-1], elements[len(elements)-1], detail.To)
+
+	case ADDITION, REMOVAL:
+		node := detail.To
+		if detail.Kind == REMOVAL {
+			node = detail.From
+		}
+
+		if node == nil || node.Kind != yamlv3.MappingNode {
+			patch.replaceList(path.DocumentIdx, elements)
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if detail.Kind == REMOVAL {
+				value = &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+			}
+
+			patch.set(path.DocumentIdx, elements, ytbx.PathElement{Idx: -1, Name: node.Content[i].Value}, value)
+		}
+
+	case ORDERCHANGE:
+		patch.replaceList(path.DocumentIdx, elements)
+
+	case POSITIONCHANGE:
+		// the path of a position change is the one of the moved list entry
+		patch.replaceList(path.DocumentIdx, elements[:len(elements)-1])
+	}
+}
+
+// replaceList sets the list at the given path to the complete list of the
+// respective `to` document
+func (patch *applyPatch) replaceList(docIdx int, elements []ytbx.PathElement) {
+	to := patch.counterpart(docIdx)
+	if to == nil {
+		return
+	}
+
+	path := ytbx.Path{DocumentIdx: docIdx, PathElements: elements}
+	value, err := ytbx.Grab(to, path.ToGoPatchStyle())
+	if err != nil {
+		return
+	}
+
+	value = redactNode(path, value, patch.report.RedactPaths)
+
+	if len(elements) == 0 {
+		patch.docs[docIdx] = value
+		return
+	}
+
+	patch.set(docIdx, elements[:len(elements)-1], elements[len(elements)-1], value)
+}
+
+// set sets the value of the last element in the container at the given path,
+// where the container is created if it does not exist in the patch yet
+func (patch *applyPatch) set(docIdx int, elements []ytbx.PathElement, last ytbx.PathElement, value *yamlv3.Node) {
+	container := patch.container(docIdx, elements, last)
+	if container == nil {
+		// The path is already covered by a value that was set as a whole
+		return
+	}
+
+	switch {
+	case last.Key != "":
+		for i, entry := range container.Content {
+			if name, ok := findValueByKey(entry, last.Key); ok && name.Value == last.Name {
+				container.Content[i] = value
+				return
+			}
+		}
+
+		container.Content = append(container.Content, value)
+
+	default:
+		for i := 0; i+1 < len(container.Content); i += 2 {
+			if container.Content[i].Value == last.Name {
+				container.Content[i+1] = value
+				return
+			}
+		}
+
+		container.Content = append(container.Content,
+			&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: last.Name},
+			value,
+		)
+	}
+}
+
+// container looks up (or creates) the mapping or list node at the given path
+// in the patch document, it returns nil if the path is already fully covered
+func (patch *applyPatch) container(docIdx int, elements []ytbx.PathElement, last ytbx.PathElement) *yamlv3.Node {
+	node := patch.document(docIdx)
+	if _, ok := patch.owned[node]; !ok {
+		return nil
+	}
+
+	for i, element := range elements {
+		next := last
+		if i+1 < len(elements) {
+			next = elements[i+1]
+		}
+
+		var child *yamlv3.Node
+		switch {
+		case element.Key != "":
+			for _, entry := range node.Content {
+				if name, ok := findValueByKey(entry, element.Key); ok && name.Value == element.Name {
+					child = entry
+					break
+				}
+			}
+
+			if child == nil {
+				child = patch.newNode(yamlv3.MappingNode)
+				child.Content = append(child.Content,
+					&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: element.Key},
+					&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: element.Name},
+				)
+				node.Content = append(node.Content, child)
+			}
+
+		default:
+			child, _ = findValueByKey(node, element.Name)
+			if child == nil {
+				kind := yamlv3.MappingNode
+				if next.Key != "" {
+					kind = yamlv3.SequenceNode
+				}
+
+				child = patch.newNode(kind)
+				node.Content = append(node.Content,
+					&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: element.Name},
+					child,
+				)
+			}
+		}
+
+		if _, ok := patch.owned[child]; !ok {
+			return nil
+		}
+
+		node = child
+	}
+
+	return node
+}
+
+// document returns the patch document for the given document index, which is
+// initialized with the fields identifying a Kubernetes resource if present
+func (patch *applyPatch) document(docIdx int) *yamlv3.Node {
+	if doc, ok := patch.docs[docIdx]; ok {
+		return doc
+	}
+
+	doc := patch.newNode(yamlv3.MappingNode)
+	patch.docs[docIdx] = doc
+
+	if docIdx < len(patch.report.From.Documents) && len(patch.report.From.Documents[docIdx].Content) > 0 {
+		from := patch.report.From.Documents[docIdx].Content[0]
+		if _, err := fqrn(from); err == nil {
+			for _, field := range []string{"apiVersion", "kind"} {
+				if value, ok := findValueByKey(from, field); ok {
+					patch.set(docIdx, nil, ytbx.PathElement{Idx: -1, Name: field}, value)
+				}
+			}
+
+			if metadata, ok := findValueByKey(from, "metadata"); ok {
+				for _, field := range []string{"name", "namespace"} {
+					if value, ok := findValueByKey(metadata, field); ok {
+						patch.set(docIdx, []ytbx.PathElement{{Idx: -1, Name: "metadata"}}, ytbx.PathElement{Idx: -1, Name: field}, value)
+					}
+				}
+			}
+		}
+	}
+
+	return doc
+}
+
+// counterpart returns the `to` document that corresponds to the `from`
+// document with the given index
+func (patch *applyPatch) counterpart(docIdx int) *yamlv3.Node {
+	if docIdx >= len(patch.report.From.Documents) {
+		return nil
+	}
+
+	from := patch.report.From.Documents[docIdx]
+	if len(from.Content) > 0 {
+		if name, err := fqrn(from.Content[0]); err == nil {
+			for _, to := range patch.report.To.Documents {
+				if len(to.Content) == 0 {
+					continue
+				}
+
+				if toName, err := fqrn(to.Content[0]); err == nil && toName == name {
+					return to
+				}
+			}
+		}
+	}
+
+	if docIdx < len(patch.report.To.Documents) {
+		return patch.report.To.Documents[docIdx]
+	}
+
+	return nil
+}
+
+func (patch *applyPatch) newNode(kind yamlv3.Kind) *yamlv3.Node {
+	node := &yamlv3.Node{Kind: kind}
+	switch kind {
+	case yamlv3.MappingNode:
+		node.Tag = "!!map"
+
+	case yamlv3.SequenceNode:
+		node.Tag = "!!seq"
+	}
+
+	patch.owned[node] = struct{}{}
+	return node
+}
@@ -0,0 +1,164 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("apply patch report", func() {
+	applyPatch := func(from, to string, compareOptions ...dyff.CompareOption) string {
+		report, err := dyff.CompareBytes([]byte(from), []byte(to), compareOptions...)
+		Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect((&dyff.ApplyPatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+		return buf.String()
+	}
+
+	Context("writing the desired delta of Kubernetes resources", func() {
+		It("should only contain changed fields with removals set to null", func() {
+			from := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: prod
+  labels:
+    team: a
+    legacy: "true"
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+        ports:
+        - containerPort: 8080
+      - name: sidecar
+        image: sidecar:1.0
+`
+
+			to := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: prod
+  labels:
+    team: b
+spec:
+  replicas: 3
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2.0
+        ports:
+        - containerPort: 8080
+      - name: sidecar
+        image: sidecar:1.0
+`
+
+			Expect(applyPatch(from, to)).To(Equal(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: prod
+  labels:
+    legacy: null
+    team: b
+spec:
+  paused: false
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app:2.0
+`))
+		})
+
+		It("should replace lists without names as a whole", func() {
+			from := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  unchanged: foo
+args:
+- one
+- two
+`
+
+			to := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  unchanged: foo
+args:
+- one
+- three
+`
+
+			Expect(applyPatch(from, to)).To(Equal(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+args:
+  - one
+  - three
+`))
+		})
+	})
+
+	Context("writing the desired delta of plain documents", func() {
+		It("should not include identifying fields if there are none", func() {
+			Expect(applyPatch("foo: bar\nkeep: me\n", "foo: baz\nkeep: me\n")).To(Equal("foo: baz\n"))
+		})
+
+		It("should redact values at matching paths, including replaced lists", func() {
+			report, err := dyff.CompareBytes(
+				[]byte("password: foo\nsecrets:\n- a\n- b\n"),
+				[]byte("password: bar\nsecrets:\n- a\n- c\n"),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.ApplyPatchReport{Report: report, RedactPaths: []string{"/password", "/secrets/*"}}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`password: '***REDACTED***'
+secrets:
+  - '***REDACTED***'
+  - '***REDACTED***'
+`))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ColorReport is a reporter that writes one line per detail, where additions
+// are green, removals are red, and modifications are yellow. Colors are not
+// used in case the output is not a terminal or the NO_COLOR environment
+// variable is set.
+type ColorReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	RedactPaths     []string
+}
+
+// WriteReport writes the colored lines to the provided writer
+func (report *ColorReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+	useColors := bunt.UseColors() && !noColor
+
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+		path := pathToString(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator, showPathRoot(report.From, diff.Path))
+
+		for _, detail := range diff.Details {
+			line, err := colorDetailLine(path, detail)
+			if err != nil {
+				return err
+			}
+
+			if !useColors {
+				line = bunt.RemoveAllEscapeSequences(line)
+			}
+
+			_, _ = writer.WriteString(line)
+			_, _ = writer.WriteString("\n")
+		}
+	}
+
+	return nil
+}
+
+func colorDetailLine(path string, detail Detail) (string, error) {
+	from, err := singleLineValue(detail.From)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := singleLineValue(detail.To)
+	if err != nil {
+		return "", err
+	}
+
+	switch detail.Kind {
+	case ADDITION:
+		return fmt.Sprintf("%s %s", green("%c", detail.Kind), path) + " " + green("%s", to), nil
+
+	case REMOVAL:
+		return fmt.Sprintf("%s %s", red("%c", detail.Kind), path) + " " + red("%s", from), nil
+
+	case MODIFICATION:
+		return fmt.Sprintf("%s %s", yellow("%c", detail.Kind), path) + " " + yellow("%s → %s", from, to), nil
+
+	default:
+		return fmt.Sprintf("%c %s %s → %s", detail.Kind, path, from, to), nil
+	}
+}
+
+// singleLineValue renders the node as one line, using the flow style for maps
+// and lists
+func singleLineValue(node *yamlv3.Node) (string, error) {
+	if node == nil {
+		return "<nil>", nil
+	}
+
+	if node = followAlias(node); node.Kind == yamlv3.ScalarNode {
+		return scalarString(node), nil
+	}
+
+	flow := *node
+	flow.Style |= yamlv3.FlowStyle
+
+	data, err := yamlv3.Marshal(&flow)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
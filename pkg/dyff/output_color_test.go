@@ -0,0 +1,94 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("color report", func() {
+	Context("reporting differences with colored lines", func() {
+		var noColor string
+		var noColorSet bool
+
+		BeforeEach(func() {
+			noColor, noColorSet = os.LookupEnv("NO_COLOR")
+			Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+			if noColorSet {
+				Expect(os.Setenv("NO_COLOR", noColor)).To(Succeed())
+			}
+		})
+
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{foo: bar}`)),
+			singleDiff("/metadata/name", dyff.REMOVAL, "foobar", nil),
+		}}
+
+		writeReport := func(reportWriter dyff.ReportWriter) string {
+			var buf bytes.Buffer
+			Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should use colors if colors are enabled", func() {
+			SetColorSettings(ON, OFF)
+
+			output := writeReport(&dyff.ColorReport{Report: report})
+			Expect(output).To(ContainSubstring("\x1b["))
+			Expect(RemoveAllEscapeSequences(output)).To(BeEquivalentTo(`± spec.replicas 1 → 2
++ metadata.labels {foo: bar}
+- metadata.name foobar
+`))
+		})
+
+		It("should not use colors if colors are disabled", func() {
+			SetColorSettings(OFF, OFF)
+
+			output := writeReport(&dyff.ColorReport{Report: report, UseGoPatchPaths: true})
+			Expect(output).ToNot(ContainSubstring("\x1b["))
+			Expect(output).To(BeEquivalentTo(`± /spec/replicas 1 → 2
++ /metadata/labels {foo: bar}
+- /metadata/name foobar
+`))
+		})
+
+		It("should not use colors if NO_COLOR is set", func() {
+			SetColorSettings(ON, OFF)
+			Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+			defer os.Unsetenv("NO_COLOR")
+
+			Expect(writeReport(&dyff.ColorReport{Report: report})).ToNot(ContainSubstring("\x1b["))
+		})
+	})
+})
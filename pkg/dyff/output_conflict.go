@@ -0,0 +1,102 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ConflictMarkerReport is a reporter that writes each detail as a block in the
+// style of a git merge conflict, with the `from` value between the `<<<<<<<`
+// and `=======` markers and the `to` value between the `=======` and
+// `>>>>>>>` markers. The markers are labeled with the path of the difference.
+// For additions and removals, the side without a value is empty.
+type ConflictMarkerReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	RedactPaths     []string
+}
+
+// WriteReport writes the conflict blocks to the provided writer
+func (report *ConflictMarkerReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	first := true
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+
+		path := PathStringWithSeparator(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator)
+		if path == "" {
+			path = "(file level)"
+		}
+
+		for _, detail := range diff.Details {
+			from, err := conflictValue(detail.From)
+			if err != nil {
+				return err
+			}
+
+			to, err := conflictValue(detail.To)
+			if err != nil {
+				return err
+			}
+
+			if !first {
+				_, _ = writer.WriteString("\n")
+			}
+
+			first = false
+			_, _ = writer.WriteString("<<<<<<< from " + path + "\n")
+			_, _ = writer.WriteString(from)
+			_, _ = writer.WriteString("=======\n")
+			_, _ = writer.WriteString(to)
+			_, _ = writer.WriteString(">>>>>>> to " + path + "\n")
+		}
+	}
+
+	return nil
+}
+
+// conflictValue returns the value as YAML with a trailing newline, or an empty
+// string if there is no value
+func conflictValue(node *yamlv3.Node) (string, error) {
+	if node == nil {
+		return "", nil
+	}
+
+	result, err := yamlString(node)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+
+	return result, nil
+}
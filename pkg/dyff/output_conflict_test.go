@@ -0,0 +1,69 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("conflict marker report", func() {
+	Context("reporting differences as conflict blocks", func() {
+		It("should write a conflict block for a modification", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.ConflictMarkerReport{Report: report, UseGoPatchPaths: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`<<<<<<< from /spec/replicas
+1
+=======
+2
+>>>>>>> to /spec/replicas
+`))
+		})
+
+		It("should write additions and removals with one side empty", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{app: foobar}`)),
+				singleDiff("/metadata/annotations", dyff.REMOVAL, yml(`{note: foo}`), nil),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.ConflictMarkerReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`<<<<<<< from metadata.labels
+=======
+app: foobar
+>>>>>>> to metadata.labels
+
+<<<<<<< from metadata.annotations
+note: foo
+=======
+>>>>>>> to metadata.annotations
+`))
+		})
+	})
+})
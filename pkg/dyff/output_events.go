@@ -0,0 +1,145 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"encoding/json"
+	"io"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// EventStreamReport is a reporter that writes one JSON event per line (NDJSON)
+// for each detail, where the type of the event is one of `added`, `removed`,
+// `modified`, `reordered`, or `moved` (the position change of a list entry)
+type EventStreamReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	RedactPaths     []string
+}
+
+type event struct {
+	Type  string      `json:"type"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// WriteReport writes the events to the provided writer
+func (report *EventStreamReport) WriteReport(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+		path := PathStringWithSeparator(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator)
+
+		for _, detail := range diff.Details {
+			var e event
+			switch detail.Kind {
+			case ADDITION:
+				e = event{Type: "added", Path: path, Value: nodeToValue(detail.To)}
+
+			case REMOVAL:
+				e = event{Type: "removed", Path: path, Value: nodeToValue(detail.From)}
+
+			case MODIFICATION:
+				e = event{Type: "modified", Path: path, From: nodeToValue(detail.From), To: nodeToValue(detail.To)}
+
+			case ORDERCHANGE:
+				e = event{Type: "reordered", Path: path, From: nodeToValue(detail.From), To: nodeToValue(detail.To)}
+
+			case POSITIONCHANGE:
+				e = event{Type: "moved", Path: path, From: nodeToValue(detail.From), To: nodeToValue(detail.To)}
+
+			default:
+				continue
+			}
+
+			if err := encoder.Encode(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// nodeToValue converts the node into plain Go types (maps with string keys,
+// slices, and scalar values), which can be serialized as JSON. A document node
+// that wraps more than one document (i.e. the addition or removal of multiple
+// whole documents) is converted into a list of the documents.
+func nodeToValue(node *yamlv3.Node) interface{} {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		switch len(node.Content) {
+		case 0:
+			return nil
+
+		case 1:
+			return nodeToValue(node.Content[0])
+		}
+
+		result := make([]interface{}, len(node.Content))
+		for i, document := range node.Content {
+			result[i] = nodeToValue(document)
+		}
+
+		return result
+
+	case yamlv3.AliasNode:
+		return nodeToValue(node.Alias)
+
+	case yamlv3.MappingNode:
+		result := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			result[followAlias(node.Content[i]).Value] = nodeToValue(node.Content[i+1])
+		}
+
+		return result
+
+	case yamlv3.SequenceNode:
+		result := make([]interface{}, len(node.Content))
+		for i, entry := range node.Content {
+			result[i] = nodeToValue(entry)
+		}
+
+		return result
+	}
+
+	if node.Tag == "!!binary" {
+		return node.Value
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+
+	return value
+}
@@ -0,0 +1,86 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gonvenience/ytbx"
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("event stream report", func() {
+	Context("reporting differences as NDJSON events", func() {
+		It("should write one typed event per detail", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				doubleDiff("/spec/list", dyff.REMOVAL, yml(`[foo]`), nil, dyff.ADDITION, nil, yml(`[bar]`)),
+				singleDiff("/spec/order", dyff.ORDERCHANGE, yml(`[a, b]`), yml(`[b, a]`)),
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{app: foobar}`)),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.EventStreamReport{Report: report, UseGoPatchPaths: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`{"type":"modified","path":"/spec/replicas","from":1,"to":2}
+{"type":"removed","path":"/spec/list","value":["foo"]}
+{"type":"added","path":"/spec/list","value":["bar"]}
+{"type":"reordered","path":"/spec/order","from":["a","b"],"to":["b","a"]}
+{"type":"added","path":"/metadata/labels","value":{"app":"foobar"}}
+`))
+		})
+
+		It("should redact values at matching paths", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/data/password", dyff.MODIFICATION, "foo", "bar"),
+				singleDiff("/data", dyff.ADDITION, nil, yml(`{token: secret, user: admin}`)),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.EventStreamReport{Report: report, UseGoPatchPaths: true, RedactPaths: []string{"/data/password", "/data/token"}}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`{"type":"modified","path":"/data/password","from":"***REDACTED***","to":"***REDACTED***"}
+{"type":"added","path":"/data","value":{"token":"***REDACTED***","user":"admin"}}
+`))
+		})
+
+		It("should include all documents of a multi-document addition", func() {
+			from := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+			)}
+
+			to := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: b}}",
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: c}}",
+			)}
+
+			report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.EventStreamReport{Report: report, UseGoPatchPaths: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`{"type":"added","path":"","value":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"b"}},{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"}}]}
+`))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/gonvenience/bunt"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const unnamedGroup = "(unnamed)"
+
+// GroupedReport is a human readable report, which groups the differences by
+// the Kubernetes style kind and name of the document they belong to
+type GroupedReport struct {
+	HumanReport
+}
+
+// WriteReport writes a grouped human readable report to the provided writer
+func (report *GroupedReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	names, groups := report.groupByDocumentName()
+	for _, name := range names {
+		_, _ = writer.WriteString(bunt.Sprintf("\n*%s*\n", name))
+
+		for _, diff := range groups[name] {
+			if err := report.generateHumanDiffOutput(writer, diff, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Finish with one last newline so that we do not end next to the prompt
+	_, _ = writer.WriteString("\n")
+	return nil
+}
+
+// groupByDocumentName partitions the differences by the name of the document
+// they belong to and returns the group names in order of their first appearance
+func (report *GroupedReport) groupByDocumentName() ([]string, map[string][]Diff) {
+	var names []string
+	var groups = map[string][]Diff{}
+
+	for _, diff := range report.Diffs {
+		name := report.documentName(diff)
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+
+		groups[name] = append(groups[name], diff)
+	}
+
+	return names, groups
+}
+
+// documentName returns the kind and name of the document the diff belongs to,
+// which is looked up in the from document first and in the to document second
+func (report *GroupedReport) documentName(diff Diff) string {
+	if diff.Path == nil {
+		return unnamedGroup
+	}
+
+	for _, documents := range [][]*yamlv3.Node{report.From.Documents, report.To.Documents} {
+		if diff.Path.DocumentIdx >= len(documents) {
+			continue
+		}
+
+		node := followAlias(documents[diff.Path.DocumentIdx])
+		if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+			node = followAlias(node.Content[0])
+		}
+
+		if node.Kind != yamlv3.MappingNode {
+			continue
+		}
+
+		kind, kindErr := nameFromPath(node, "kind")
+		name, nameErr := nameFromPath(node, "metadata.name")
+		if kindErr == nil && nameErr == nil {
+			return fmt.Sprintf("%s/%s", kind, name)
+		}
+	}
+
+	return unnamedGroup
+}
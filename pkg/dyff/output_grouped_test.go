@@ -0,0 +1,116 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+
+	"github.com/gonvenience/ytbx"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("grouped report", func() {
+	Context("reporting differences grouped by document", func() {
+		BeforeEach(func() {
+			SetColorSettings(OFF, OFF)
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+		})
+
+		It("should group the differences by kind and name of the document", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`---
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 1
+`, `---
+kind: Service
+metadata:
+  name: nginx
+spec:
+  port: 80
+`, `---
+foo: bar
+`)}
+
+			to := ytbx.InputFile{Documents: multiDoc(`---
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`, `---
+kind: Service
+metadata:
+  name: nginx
+spec:
+  port: 8080
+`, `---
+foo: BAR
+`)}
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			reportWriter := &dyff.GroupedReport{
+				HumanReport: dyff.HumanReport{
+					Report:     report,
+					OmitHeader: true,
+				},
+			}
+
+			var buf bytes.Buffer
+			Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`
+Deployment/nginx
+
+spec.replicas
+  ± value change
+    - 1
+    + 3
+
+Service/nginx
+
+spec.port
+  ± value change
+    - 80
+    + 8080
+
+(unnamed)
+
+foo
+  ± value change
+    - bar
+    + BAR
+
+`))
+		})
+	})
+})
@@ -30,6 +30,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gonvenience/bunt"
@@ -56,6 +57,122 @@ type HumanReport struct {
 	DoNotInspectCerts    bool
 	OmitHeader           bool
 	UseGoPatchPaths      bool
+	UseJSONPaths         bool
+	CompactScalars       bool
+	StrictRendering      bool
+	RedactPaths          []string
+	ForceHexDump         bool
+	Labels               *Labels
+	ShowFooter           bool
+	BinaryDetection      BinaryDetection
+	PathSeparator        string
+}
+
+// BinaryDetection defines how binary values are detected in the human
+// readable report
+type BinaryDetection string
+
+// Supported modes of the binary detection, an empty mode is the same as auto
+const (
+	// BinaryDetectionAuto treats values with the `!!binary` tag as binary
+	// data, which is shown as a text difference if it decodes to text and as
+	// a hex dump otherwise
+	BinaryDetectionAuto BinaryDetection = "auto"
+
+	// BinaryDetectionNever treats all values as text, so that values with the
+	// `!!binary` tag are shown as their base64 encoded string
+	BinaryDetectionNever BinaryDetection = "never"
+)
+
+// Labels contains the texts that are used in the human readable report, for
+// example to translate the report into another language. Use DefaultLabels as
+// a starting point and override the texts as required. The TypeChange text is
+// a format string with the from and to type as arguments.
+type Labels struct {
+	Between  string
+	And      string
+	Returned string
+
+	Added                string
+	Removed              string
+	ValueChange          string
+	TypeChange           string
+	ContentChange        string
+	CertificateChange    string
+	WhitespaceOnlyChange string
+	MultilineValueChange string
+	OrderChanged         string
+	PositionChanged      string
+
+	Difference  string
+	Differences string
+	Document    string
+	Documents   string
+	MapEntry    string
+	MapEntries  string
+	ListEntry   string
+	ListEntries string
+	Insert      string
+	Inserts     string
+	Deletion    string
+	Deletions   string
+	Change      string
+	Changes     string
+
+	// Plural renders an amount together with the singular or plural noun
+	Plural func(amount int, singular string, plural string) string
+}
+
+// DefaultLabels returns the English texts of the human readable report
+func DefaultLabels() Labels {
+	return Labels{
+		Between:  "between",
+		And:      "and",
+		Returned: "returned",
+
+		Added:                "added",
+		Removed:              "removed",
+		ValueChange:          "value change",
+		TypeChange:           "type change from %s to %s",
+		ContentChange:        "content change",
+		CertificateChange:    "certificate change",
+		WhitespaceOnlyChange: "whitespace only change",
+		MultilineValueChange: "value change in multiline text",
+		OrderChanged:         "order changed",
+		PositionChanged:      "position changed from index %s to %s",
+
+		Difference:  "difference",
+		Differences: "differences",
+		Document:    "document",
+		Documents:   "documents",
+		MapEntry:    "map entry",
+		MapEntries:  "map entries",
+		ListEntry:   "list entry",
+		ListEntries: "list entries",
+		Insert:      "insert",
+		Inserts:     "inserts",
+		Deletion:    "deletion",
+		Deletions:   "deletions",
+		Change:      "change",
+		Changes:     "changes",
+
+		Plural: func(amount int, singular string, plural string) string {
+			return text.Plural(amount, singular, plural)
+		},
+	}
+}
+
+func (report *HumanReport) labels() Labels {
+	if report.Labels == nil {
+		return DefaultLabels()
+	}
+
+	labels := *report.Labels
+	if labels.Plural == nil {
+		labels.Plural = DefaultLabels().Plural
+	}
+
+	return labels
 }
 
 // WriteReport writes a human readable report to the provided writer
@@ -63,21 +180,25 @@ func (report *HumanReport) WriteReport(out io.Writer) error {
 	writer := bufio.NewWriter(out)
 	defer writer.Flush()
 
-	// Only show the document index if there is more than one document to show
-	showPathRoot := len(report.From.Documents) > 1
+	labels := report.labels()
 
 	// Show banner if enabled
 	if !report.OmitHeader {
+		width := 8
+		for _, label := range []string{labels.Between, labels.And, labels.Returned} {
+			width = max(width, utf8.RuneCountInString(label))
+		}
+
 		var header = fmt.Sprintf(`     _        __  __
-   _| |_   _ / _|/ _|  between %s
- / _' | | | | |_| |_       and %s
+   _| |_   _ / _|/ _| %*s %s
+ / _' | | | | |_| |_  %*s %s
 | (_| | |_| |  _|  _|
- \__,_|\__, |_| |_|   returned %s
+ \__,_|\__, |_| |_|   %*s %s
         |___/
 `,
-			ytbx.HumanReadableLocationInformation(report.From),
-			ytbx.HumanReadableLocationInformation(report.To),
-			bunt.Style(text.Plural(len(report.Diffs), "difference"), bunt.Bold()))
+			width, labels.Between, ytbx.HumanReadableLocationInformation(report.From),
+			width, labels.And, ytbx.HumanReadableLocationInformation(report.To),
+			width, labels.Returned, bunt.Style(labels.Plural(len(report.Diffs), labels.Difference, labels.Differences), bunt.Bold()))
 
 		_, _ = writer.WriteString(bunt.Style(
 			header,
@@ -98,22 +219,84 @@ func (report *HumanReport) WriteReport(out io.Writer) error {
 		))
 	}
 
+	// Show warnings, for example about differences that are not reported, before
+	// the differences, so that they are not overlooked
+	for i, warning := range report.Warnings {
+		if i == 0 {
+			_, _ = writer.WriteString("\n")
+		}
+
+		_, _ = writer.WriteString(yellow("⚠ %s", warning) + "\n")
+	}
+
 	// Loop over the diff and generate each report into the buffer
 	for _, diff := range report.Diffs {
-		if err := report.generateHumanDiffOutput(writer, diff, report.UseGoPatchPaths, showPathRoot); err != nil {
+		if err := report.generateHumanDiffOutput(writer, diff, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), showPathRoot(report.From, diff.Path)); err != nil {
 			return err
 		}
 	}
 
+	if report.ShowFooter {
+		report.writeFooter(writer, labels)
+	}
+
 	// Finish with one last newline so that we do not end next to the prompt
 	_, _ = writer.WriteString("\n")
 	return nil
 }
 
+// writeFooter writes the total number of changes by kind, and a legend of the
+// colors used for the kinds in case colors are enabled
+func (report *HumanReport) writeFooter(output stringWriter, labels Labels) {
+	counts := map[rune]int{}
+	var total int
+	for _, diff := range report.Diffs {
+		for _, detail := range diff.Details {
+			counts[detail.Kind]++
+			total++
+		}
+	}
+
+	summary := fmt.Sprintf("%d %c, %d %c, %d %c",
+		counts[ADDITION], ADDITION,
+		counts[REMOVAL], REMOVAL,
+		counts[MODIFICATION], MODIFICATION,
+	)
+
+	if counts[ORDERCHANGE] > 0 {
+		summary += fmt.Sprintf(", %d %c", counts[ORDERCHANGE], ORDERCHANGE)
+	}
+
+	if counts[POSITIONCHANGE] > 0 {
+		summary += fmt.Sprintf(", %d %c", counts[POSITIONCHANGE], POSITIONCHANGE)
+	}
+
+	_, _ = output.WriteString(fmt.Sprintf("\nΣ %s (%s)\n",
+		bold("%s", labels.Plural(total, labels.Change, labels.Changes)),
+		summary,
+	))
+
+	if bunt.UseColors() {
+		_, _ = output.WriteString(strings.Join([]string{
+			green("%c %s", ADDITION, labels.Added),
+			red("%c %s", REMOVAL, labels.Removed),
+			yellow("%c %s", MODIFICATION, labels.ValueChange),
+			yellow("%c %s", ORDERCHANGE, labels.OrderChanged),
+		}, "  ") + "\n")
+	}
+}
+
 // generateHumanDiffOutput creates a human readable report of the provided diff and writes this into the given bytes buffer. There is an optional flag to indicate whether the document index (which documents of the input file) should be included in the report of the path of the difference.
-func (report *HumanReport) generateHumanDiffOutput(output stringWriter, diff Diff, useGoPatchPaths bool, showPathRoot bool) error {
+func (report *HumanReport) generateHumanDiffOutput(output stringWriter, diff Diff, style PathStyle, showPathRoot bool) error {
+	diff = redactDiff(diff, report.RedactPaths)
+
 	_, _ = output.WriteString("\n")
-	_, _ = output.WriteString(pathToString(diff.Path, useGoPatchPaths, showPathRoot))
+	if diff.Source != "" {
+		// Differences of merged reports are prefixed with their origin
+		_, _ = output.WriteString(dimgray("%s:", diff.Source) + " ")
+	}
+
+	_, _ = output.WriteString(pathToString(diff.Path, style, report.PathSeparator, showPathRoot))
 	_, _ = output.WriteString("\n")
 
 	blocks := make([]string, len(diff.Details))
@@ -151,6 +334,9 @@ func (report *HumanReport) generateHumanDetailOutput(detail Detail) (string, err
 
 	case ORDERCHANGE:
 		return report.generateHumanDetailOutputOrderchange(detail)
+
+	case POSITIONCHANGE:
+		return yellow("%c %s\n", POSITIONCHANGE, fmt.Sprintf(report.labels().PositionChanged, detail.From.Value, detail.To.Value)), nil
 	}
 
 	return "", fmt.Errorf("unsupported detail type %c", detail.Kind)
@@ -158,18 +344,21 @@ func (report *HumanReport) generateHumanDetailOutput(detail Detail) (string, err
 
 func (report *HumanReport) generateHumanDetailOutputAddition(detail Detail) (string, error) {
 	var output bytes.Buffer
+	labels := report.labels()
 
 	switch detail.To.Kind {
 	case yamlv3.SequenceNode:
-		_, _ = output.WriteString(yellow("%c %s added:\n",
+		_, _ = output.WriteString(yellow("%c %s %s:\n",
 			ADDITION,
-			text.Plural(len(detail.To.Content), "list entry", "list entries"),
+			labels.Plural(len(detail.To.Content), labels.ListEntry, labels.ListEntries),
+			labels.Added,
 		))
 
 	case yamlv3.MappingNode:
-		_, _ = output.WriteString(yellow("%c %s added:\n",
+		_, _ = output.WriteString(yellow("%c %s %s:\n",
 			ADDITION,
-			text.Plural(len(detail.To.Content)/2, "map entry", "map entries"),
+			labels.Plural(len(detail.To.Content)/2, labels.MapEntry, labels.MapEntries),
+			labels.Added,
 		))
 	}
 
@@ -186,21 +375,23 @@ func (report *HumanReport) generateHumanDetailOutputAddition(detail Detail) (str
 
 func (report *HumanReport) generateHumanDetailOutputRemoval(detail Detail) (string, error) {
 	var output bytes.Buffer
+	labels := report.labels()
 
 	switch detail.From.Kind {
 	case yamlv3.DocumentNode:
-		_, _ = fmt.Fprint(&output, yellow("%c %s removed:\n",
+		_, _ = fmt.Fprint(&output, yellow("%c %s %s:\n",
 			REMOVAL,
-			text.Plural(len(detail.From.Content), "document"),
+			labels.Plural(len(detail.From.Content), labels.Document, labels.Documents),
+			labels.Removed,
 		))
 
 	case yamlv3.SequenceNode:
-		text := text.Plural(len(detail.From.Content), "list entry", "list entries")
-		_, _ = output.WriteString(yellow("%c %s removed:\n", REMOVAL, text))
+		text := labels.Plural(len(detail.From.Content), labels.ListEntry, labels.ListEntries)
+		_, _ = output.WriteString(yellow("%c %s %s:\n", REMOVAL, text, labels.Removed))
 
 	case yamlv3.MappingNode:
-		text := text.Plural(len(detail.From.Content)/2, "map entry", "map entries")
-		_, _ = output.WriteString(yellow("%c %s removed:\n", REMOVAL, text))
+		text := labels.Plural(len(detail.From.Content)/2, labels.MapEntry, labels.MapEntries)
+		_, _ = output.WriteString(yellow("%c %s %s:\n", REMOVAL, text, labels.Removed))
 	}
 
 	ytbx.RestructureObject(detail.From)
@@ -216,10 +407,28 @@ func (report *HumanReport) generateHumanDetailOutputRemoval(detail Detail) (stri
 
 func (report *HumanReport) generateHumanDetailOutputModification(detail Detail) (string, error) {
 	var output bytes.Buffer
+	labels := report.labels()
 	fromType := humanReadableType(detail.From)
 	toType := humanReadableType(detail.To)
 
+	if report.BinaryDetection == BinaryDetectionNever {
+		if fromType == "binary" {
+			fromType = "string"
+		}
+
+		if toType == "binary" {
+			toType = "string"
+		}
+	}
+
 	switch {
+	case report.CompactScalars && isCompactScalarChange(detail, fromType, toType):
+		_, _ = output.WriteString(fmt.Sprintf("%s %s → %s\n",
+			yellow("%c", MODIFICATION),
+			red("%s", scalarString(detail.From)),
+			green("%s", scalarString(detail.To)),
+		))
+
 	case fromType == "string" && toType == "string":
 		// delegate to special string output
 		report.writeStringDiff(
@@ -229,17 +438,23 @@ func (report *HumanReport) generateHumanDetailOutputModification(detail Detail)
 		)
 
 	case fromType == "binary" && toType == "binary":
-		from, err := base64.StdEncoding.DecodeString(detail.From.Value)
+		from, err := decodeBase64(detail.From.Value)
 		if err != nil {
 			return "", err
 		}
 
-		to, err := base64.StdEncoding.DecodeString(detail.To.Value)
+		to, err := decodeBase64(detail.To.Value)
 		if err != nil {
 			return "", err
 		}
 
-		_, _ = output.WriteString(yellow("%c content change\n", MODIFICATION))
+		// binary data that is actually text is shown as a text difference
+		if !report.ForceHexDump && isText(from) && isText(to) {
+			report.writeStringDiff(&output, string(from), string(to))
+			break
+		}
+
+		_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.ContentChange))
 		report.writeTextBlocks(&output, 0,
 			red("%s", createStringWithPrefix("  - ", hex.Dump(from))),
 			green("%s", createStringWithPrefix("  + ", hex.Dump(to))),
@@ -247,15 +462,15 @@ func (report *HumanReport) generateHumanDetailOutputModification(detail Detail)
 
 	default:
 		if fromType != toType {
-			_, _ = output.WriteString(yellow("%c type change from %s to %s\n",
+			_, _ = output.WriteString(yellow("%c %s\n",
 				MODIFICATION,
-				italic(fromType),
-				italic(toType),
+				fmt.Sprintf(labels.TypeChange, italic(fromType), italic(toType)),
 			))
 
 		} else {
-			_, _ = output.WriteString(yellow("%c value change\n",
+			_, _ = output.WriteString(yellow("%c %s\n",
 				MODIFICATION,
+				labels.ValueChange,
 			))
 		}
 
@@ -278,8 +493,13 @@ func (report *HumanReport) generateHumanDetailOutputModification(detail Detail)
 
 func (report *HumanReport) generateHumanDetailOutputOrderchange(detail Detail) (string, error) {
 	var output bytes.Buffer
+	labels := report.labels()
+
+	if report.StrictRendering && (detail.From.Kind != yamlv3.SequenceNode || detail.To.Kind != yamlv3.SequenceNode) {
+		return "", fmt.Errorf("unsupported order change between %s and %s", humanReadableType(detail.From), humanReadableType(detail.To))
+	}
 
-	_, _ = output.WriteString(yellow("%c order changed\n", ORDERCHANGE))
+	_, _ = output.WriteString(yellow("%c %s\n", ORDERCHANGE, labels.OrderChanged))
 	switch detail.From.Kind {
 	case yamlv3.SequenceNode:
 		asStringList := func(sequenceNode *yamlv3.Node) ([]string, error) {
@@ -327,15 +547,16 @@ func (report *HumanReport) generateHumanDetailOutputOrderchange(detail Detail) (
 }
 
 func (report *HumanReport) writeStringDiff(output stringWriter, from string, to string) {
+	labels := report.labels()
 	fromCertText, toCertText, err := report.LoadX509Certs(from, to)
 
 	switch {
 	case err == nil:
-		_, _ = output.WriteString(yellow("%c certificate change\n", MODIFICATION))
+		_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.CertificateChange))
 		_, _ = output.WriteString(report.highlightByLine(fromCertText, toCertText))
 
 	case isWhitespaceOnlyChange(from, to):
-		_, _ = output.WriteString(yellow("%c whitespace only change\n", MODIFICATION))
+		_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.WhitespaceOnlyChange))
 		report.writeTextBlocks(output, 0,
 			red("%s", createStringWithPrefix("  - ", showWhitespaceCharacters(from))),
 			green("%s", createStringWithPrefix("  + ", showWhitespaceCharacters(to))),
@@ -343,7 +564,7 @@ func (report *HumanReport) writeStringDiff(output stringWriter, from string, to
 
 	case isMultiLine(from, to):
 		if !bunt.UseColors() {
-			_, _ = output.WriteString(yellow("%c value change\n", MODIFICATION))
+			_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.ValueChange))
 			report.writeTextBlocks(output, 0,
 				red("%s", createStringWithPrefix("  - ", from)),
 				green("%s", createStringWithPrefix("  + ", to)),
@@ -375,24 +596,24 @@ func (report *HumanReport) writeStringDiff(output stringWriter, from string, to
 
 			var insDelDetails []string
 			if ins > 0 {
-				insDelDetails = append(insDelDetails, text.Plural(ins, "insert"))
+				insDelDetails = append(insDelDetails, labels.Plural(ins, labels.Insert, labels.Inserts))
 			}
 			if del > 0 {
-				insDelDetails = append(insDelDetails, text.Plural(del, "deletion"))
+				insDelDetails = append(insDelDetails, labels.Plural(del, labels.Deletion, labels.Deletions))
 			}
 
-			_, _ = output.WriteString(yellow("%c value change in multiline text (%s)\n", MODIFICATION, strings.Join(insDelDetails, ", ")))
+			_, _ = output.WriteString(yellow("%c %s (%s)\n", MODIFICATION, labels.MultilineValueChange, strings.Join(insDelDetails, ", ")))
 			_, _ = output.WriteString(createStringWithPrefix("    ", buf.String()))
 		}
 
 	case isMinorChange(from, to, report.MinorChangeThreshold):
-		_, _ = output.WriteString(yellow("%c value change\n", MODIFICATION))
+		_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.ValueChange))
 		diffs := diffmatchpatch.New().DiffMain(from, to, false)
 		_, _ = output.WriteString(highlightRemovals(diffs))
 		_, _ = output.WriteString(highlightAdditions(diffs))
 
 	default:
-		_, _ = output.WriteString(yellow("%c value change\n", MODIFICATION))
+		_, _ = output.WriteString(yellow("%c %s\n", MODIFICATION, labels.ValueChange))
 		_, _ = output.WriteString(red("%s", createStringWithPrefix("  - ", from)))
 		_, _ = output.WriteString(green("%s", createStringWithPrefix("  + ", to)))
 	}
@@ -461,6 +682,60 @@ func humanReadableType(node *yamlv3.Node) string {
 	panic(fmt.Errorf("unknown and therefore unsupported kind %v", node.Kind))
 }
 
+// decodeBase64 decodes the provided string using the standard base64 encoding
+// and falls back to the URL-safe encoding in case the standard one fails
+func decodeBase64(input string) ([]byte, error) {
+	result, err := base64.StdEncoding.DecodeString(input)
+	if err != nil {
+		if urlResult, urlErr := base64.URLEncoding.DecodeString(input); urlErr == nil {
+			return urlResult, nil
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// isText returns whether the data is valid UTF-8 text without any control
+// characters other than whitespace
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isCompactScalarChange returns whether the modification is a change of two
+// single-line scalars, which can be rendered compact on one line
+func isCompactScalarChange(detail Detail, fromType string, toType string) bool {
+	from, to := followAlias(detail.From), followAlias(detail.To)
+	if from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode {
+		return false
+	}
+
+	if fromType == "binary" || toType == "binary" {
+		return false
+	}
+
+	return !isMultiLine(from.Value, to.Value) && !isWhitespaceOnlyChange(from.Value, to.Value)
+}
+
+func scalarString(node *yamlv3.Node) string {
+	if node = followAlias(node); node.Tag == "!!null" {
+		return "<nil>"
+	}
+
+	return node.Value
+}
+
 func highlightRemovals(diffs []diffmatchpatch.Diff) string {
 	var buf bytes.Buffer
 
@@ -751,7 +1026,15 @@ func styledGoPatchPath(path *ytbx.Path) string {
 	return strings.Join(sections, "/")
 }
 
-func styledDotStylePath(path *ytbx.Path) string {
+func styledJSONPath(path *ytbx.Path) string {
+	if path == nil {
+		return bunt.Sprintf("*(file level)*")
+	}
+
+	return bold("%s", jsonPathString(path))
+}
+
+func styledDotStylePath(path *ytbx.Path, separator string) string {
 	if path == nil {
 		return bunt.Sprintf("*(file level)*")
 	}
@@ -765,15 +1048,19 @@ func styledDotStylePath(path *ytbx.Path) string {
 	for _, element := range path.PathElements {
 		switch {
 		case element.Key == "" && element.Name != "":
-			sections = append(sections, bunt.Sprintf("*%s*", element.Name))
+			sections = append(sections, bunt.Sprintf("*%s*", escapePathElement(element.Name, separator)))
 
 		case element.Key != "" && element.Name != "":
-			sections = append(sections, bunt.Sprintf("_*%s*_", element.Name))
+			sections = append(sections, bunt.Sprintf("_*%s*_", escapePathElement(element.Name, separator)))
 
 		case element.Idx >= 0:
 			sections = append(sections, bunt.Sprintf("*%d*", element.Idx))
 		}
 	}
 
-	return strings.Join(sections, ".")
+	if separator == "" {
+		separator = "."
+	}
+
+	return strings.Join(sections, separator)
 }
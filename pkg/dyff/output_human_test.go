@@ -21,6 +21,7 @@
 package dyff_test
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -29,6 +30,7 @@ import (
 	. "github.com/gonvenience/bunt"
 
 	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
 
 	"github.com/homeport/dyff/pkg/dyff"
 )
@@ -118,6 +120,265 @@ input: |+
 			)
 		})
 
+		It("should show a binary data difference of URL-safe base64 encoded data", func() {
+			content := dyff.Diff{
+				Path: path("/some/yaml/structure/binary"),
+				Details: []dyff.Detail{{
+					Kind: dyff.MODIFICATION,
+					From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "-_8="},
+					To:   &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "_-8="},
+				}},
+			}
+
+			Expect(humanDiff(content)).To(BeEquivalentTo(`
+some.yaml.structure.binary
+  ± content change
+    - 00000000  fb ff                                             |..|
+
+
+    + 00000000  ff ef                                             |..|
+
+
+
+`))
+		})
+
+		It("should show a text difference for binary data that decodes to text", func() {
+			content := dyff.Diff{
+				Path: path("/some/yaml/structure/binary"),
+				Details: []dyff.Detail{{
+					Kind: dyff.MODIFICATION,
+					From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "aGVsbG8gd29ybGQ="},
+					To:   &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "aGVsbG8gdGhlcmU="},
+				}},
+			}
+
+			output := humanDiff(content)
+			Expect(output).ToNot(ContainSubstring("content change"))
+			Expect(output).To(ContainSubstring("hello world"))
+			Expect(output).To(ContainSubstring("hello there"))
+
+			reporter := dyff.HumanReport{
+				Report:       dyff.Report{Diffs: []dyff.Diff{content}},
+				OmitHeader:   true,
+				ForceHexDump: true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("content change"))
+			Expect(buf.String()).To(ContainSubstring("68 65 6c 6c 6f 20 77 6f  72 6c 64"))
+		})
+
+		It("should show a compact scalar change if configured", func() {
+			reporter := dyff.HumanReport{
+				Report: dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/some/yaml/structure/string", dyff.MODIFICATION, "fOObar?", "Foobar!"),
+					singleDiff("/some/yaml/structure/int", dyff.MODIFICATION, 12, 147),
+					singleDiff("/some/yaml/structure/text", dyff.MODIFICATION, "foo\nbar\n", "foo\nBAR\n"),
+				}},
+				OmitHeader:     true,
+				CompactScalars: true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`
+some.yaml.structure.string
+  ± fOObar? → Foobar!
+
+some.yaml.structure.int
+  ± 12 → 147
+
+some.yaml.structure.text
+  ± value change
+    - foo     + foo
+      bar       BAR
+
+
+`))
+		})
+
+		It("should detect binary values based on the configured binary detection", func() {
+			content := dyff.Diff{
+				Path: path("/data/config"),
+				Details: []dyff.Detail{{
+					Kind: dyff.MODIFICATION,
+					From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "aGVsbG8gd29ybGQ="},
+					To:   &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!binary", Value: "aGVsbG8gdGhlcmU="},
+				}},
+			}
+
+			write := func(mode dyff.BinaryDetection) string {
+				reporter := dyff.HumanReport{
+					Report:          dyff.Report{Diffs: []dyff.Diff{content}},
+					OmitHeader:      true,
+					BinaryDetection: mode,
+				}
+
+				var buf bytes.Buffer
+				Expect(reporter.WriteReport(&buf)).To(Succeed())
+				return buf.String()
+			}
+
+			for _, mode := range []dyff.BinaryDetection{"", dyff.BinaryDetectionAuto} {
+				Expect(write(mode)).To(ContainSubstring("hello world"))
+				Expect(write(mode)).ToNot(ContainSubstring("content change"))
+			}
+
+			Expect(write(dyff.BinaryDetectionNever)).To(ContainSubstring("aGVsbG8gd29ybGQ="))
+			Expect(write(dyff.BinaryDetectionNever)).ToNot(ContainSubstring("hello world"))
+		})
+
+		It("should show a footer with the totals by kind if configured", func() {
+			reporter := dyff.HumanReport{
+				Report: dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/some/yaml/structure/int", dyff.MODIFICATION, 12, 147),
+					doubleDiff("/some/yaml/structure/list", dyff.REMOVAL, yml(`[foo]`), nil, dyff.ADDITION, nil, yml(`[bar, baz]`)),
+				}},
+				OmitHeader:     true,
+				CompactScalars: true,
+				ShowFooter:     true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`
+some.yaml.structure.int
+  ± 12 → 147
+
+some.yaml.structure.list
+  - one list entry removed:     + two list entries added:
+    - foo                         - bar
+                                  - baz
+
+Σ three changes (1 +, 1 -, 1 ±)
+
+`))
+		})
+
+		It("should show a color legend in the footer if colors are enabled", func() {
+			SetColorSettings(ON, ON)
+			defer SetColorSettings(AUTO, AUTO)
+
+			reporter := dyff.HumanReport{
+				Report: dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/order", dyff.ORDERCHANGE, yml(`[a, b]`), yml(`[b, a]`)),
+				}},
+				OmitHeader: true,
+				ShowFooter: true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(RemoveAllEscapeSequences(buf.String())).To(HaveSuffix(`
+Σ one change (0 +, 0 -, 0 ±, 1 ⇆)
++ added  - removed  ± value change  ⇆ order changed
+
+`))
+		})
+
+		It("should fail to render an order change of a map in strict mode", func() {
+			content := singleDiff("/some/yaml/structure/map", dyff.ORDERCHANGE, yml(`{foo: bar}`), yml(`{bar: foo}`))
+
+			reporter := dyff.HumanReport{
+				Report:          dyff.Report{Diffs: []dyff.Diff{content}},
+				OmitHeader:      true,
+				StrictRendering: true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(MatchError("unsupported order change between map and map"))
+
+			reporter.StrictRendering = false
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+		})
+
+		It("should redact values at matching paths", func() {
+			reporter := dyff.HumanReport{
+				Report: dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/data/password", dyff.MODIFICATION, "secret1", "secret2"),
+					singleDiff("/data", dyff.ADDITION, nil, yml(`{token: secret3}`)),
+					singleDiff("/data/user", dyff.MODIFICATION, "foo", "bar"),
+				}},
+				OmitHeader:     true,
+				CompactScalars: true,
+				RedactPaths:    []string{"/data/password", "/data/token"},
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).ToNot(ContainSubstring("secret"))
+			Expect(buf.String()).To(ContainSubstring("***REDACTED***"))
+			Expect(buf.String()).To(ContainSubstring("foo → bar"))
+		})
+
+		It("should redact values in added and removed documents", func() {
+			from := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: Secret, metadata: {name: foo}, data: {password: hunter1}}",
+			)}
+
+			to := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: Secret, metadata: {name: bar}, data: {password: hunter2}}",
+			)}
+
+			report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).To(HaveLen(1))
+			Expect(report.Diffs[0].Path).To(BeNil())
+
+			reporter := dyff.HumanReport{
+				Report:      report,
+				OmitHeader:  true,
+				RedactPaths: []string{"/data/password"},
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).ToNot(ContainSubstring("hunter"))
+			Expect(buf.String()).To(ContainSubstring("***REDACTED***"))
+			Expect(buf.String()).To(ContainSubstring("foo"))
+			Expect(buf.String()).To(ContainSubstring("bar"))
+		})
+
+		It("should use custom labels if configured", func() {
+			labels := dyff.DefaultLabels()
+			labels.Added = "hinzugefügt"
+			labels.ValueChange = "Wertänderung"
+			labels.MapEntry, labels.MapEntries = "Eintrag", "Einträge"
+			labels.Plural = func(amount int, singular string, plural string) string {
+				if amount == 1 {
+					return fmt.Sprintf("%d %s", amount, singular)
+				}
+
+				return fmt.Sprintf("%d %s", amount, plural)
+			}
+
+			reporter := dyff.HumanReport{
+				Report: dyff.Report{Diffs: []dyff.Diff{
+					singleDiff("/some/yaml/structure/map", dyff.ADDITION, nil, yml(`{foo: bar, bar: foo}`)),
+					singleDiff("/some/yaml/structure/int", dyff.MODIFICATION, 12, 147),
+				}},
+				OmitHeader: true,
+				Labels:     &labels,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(BeEquivalentTo(`
+some.yaml.structure.map
+  + 2 Einträge hinzugefügt:
+    foo: bar
+    bar: foo
+
+some.yaml.structure.int
+  ± Wertänderung
+    - 12
+    + 147
+
+`))
+		})
+
 		It("should show the testbed results as expected", func() {
 			compareAgainstExpected("../../assets/testbed/from.yml",
 				"../../assets/testbed/to.yml",
@@ -0,0 +1,58 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"io"
+)
+
+// MultiReport writes one report in multiple formats to multiple writers, so
+// that the comparison only needs to be done once
+type MultiReport struct {
+	Report
+	Outputs []ReportOutput
+}
+
+// ReportOutput is one output of a MultiReport, which consists of the writer
+// to write to and a function to create the report writer for the report. In
+// case no writer is set, the writer provided to WriteReport is used.
+type ReportOutput struct {
+	Out       io.Writer
+	NewWriter func(Report) ReportWriter
+}
+
+// WriteReport writes the report to all configured outputs, it stops at the
+// first output that fails
+func (report *MultiReport) WriteReport(out io.Writer) error {
+	for i, output := range report.Outputs {
+		target := output.Out
+		if target == nil {
+			target = out
+		}
+
+		if err := output.NewWriter(report.Report).WriteReport(target); err != nil {
+			return fmt.Errorf("failed to write report output #%d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) WriteReport(_ io.Writer) error {
+	return fmt.Errorf("failed on purpose")
+}
+
+var _ = Describe("multi report", func() {
+	report := dyff.Report{Diffs: []dyff.Diff{
+		singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+	}}
+
+	Context("writing a report to multiple outputs", func() {
+		It("should write the same report in all formats", func() {
+			var events, human bytes.Buffer
+			multi := dyff.MultiReport{
+				Report: report,
+				Outputs: []dyff.ReportOutput{
+					{Out: &events, NewWriter: func(r dyff.Report) dyff.ReportWriter {
+						return &dyff.EventStreamReport{Report: r, UseGoPatchPaths: true}
+					}},
+					{Out: &human, NewWriter: func(r dyff.Report) dyff.ReportWriter {
+						return &dyff.HumanReport{Report: r, OmitHeader: true, CompactScalars: true}
+					}},
+				},
+			}
+
+			Expect(multi.WriteReport(nil)).To(Succeed())
+			Expect(events.String()).To(Equal(`{"type":"modified","path":"/spec/replicas","from":1,"to":2}` + "\n"))
+			Expect(human.String()).To(Equal("\nspec.replicas\n  ± 1 → 2\n\n"))
+		})
+
+		It("should use the provided writer for outputs without a writer", func() {
+			var buf bytes.Buffer
+			multi := dyff.MultiReport{
+				Report: report,
+				Outputs: []dyff.ReportOutput{
+					{NewWriter: func(r dyff.Report) dyff.ReportWriter { return &dyff.BriefReport{Report: r} }},
+				},
+			}
+
+			Expect(multi.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).ToNot(BeEmpty())
+		})
+
+		It("should fail with the output that failed", func() {
+			multi := dyff.MultiReport{
+				Report: report,
+				Outputs: []dyff.ReportOutput{
+					{Out: io.Discard, NewWriter: func(r dyff.Report) dyff.ReportWriter { return &dyff.BriefReport{Report: r} }},
+					{Out: io.Discard, NewWriter: func(dyff.Report) dyff.ReportWriter { return failingWriter{} }},
+				},
+			}
+
+			err := multi.WriteReport(nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("failed to write report output #2: failed on purpose"))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// OTelLogReport is a reporter that writes one JSON log record per line for
+// each detail, using the field names of the OpenTelemetry log data model for
+// the timestamp and severity. Each record has the fields `timestamp` (RFC 3339
+// with nanoseconds), `severity` and `severity_number` (always `INFO` and 9),
+// `body`, `path`, `change_kind` (one of `addition`, `removal`, `modification`,
+// `order-change`, or `position-change`), as well as `old` and `new` with the
+// values, which are null if there is no such value.
+type OTelLogReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	RedactPaths     []string
+
+	// Timestamp is used for all records, the current time is used if not set
+	Timestamp time.Time
+}
+
+type otelLogRecord struct {
+	Timestamp      string      `json:"timestamp"`
+	Severity       string      `json:"severity"`
+	SeverityNumber int         `json:"severity_number"`
+	Body           string      `json:"body"`
+	Path           string      `json:"path"`
+	ChangeKind     string      `json:"change_kind"`
+	Old            interface{} `json:"old"`
+	New            interface{} `json:"new"`
+}
+
+// WriteReport writes the log records to the provided writer
+func (report *OTelLogReport) WriteReport(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	timestamp := report.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+		path := PathStringWithSeparator(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator)
+
+		for _, detail := range diff.Details {
+			kind := kindName(detail.Kind)
+			record := otelLogRecord{
+				Timestamp:      timestamp.UTC().Format(time.RFC3339Nano),
+				Severity:       "INFO",
+				SeverityNumber: 9,
+				Body:           kind + " at " + path,
+				Path:           path,
+				ChangeKind:     kind,
+				Old:            nodeToValue(detail.From),
+				New:            nodeToValue(detail.To),
+			}
+
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("OpenTelemetry log report", func() {
+	Context("reporting differences as structured log records", func() {
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{app: foobar}`)),
+		}}
+
+		It("should write one record per detail with the log data model fields", func() {
+			var buf bytes.Buffer
+			Expect((&dyff.OTelLogReport{Report: report, UseGoPatchPaths: true}).WriteReport(&buf)).To(Succeed())
+
+			var records []map[string]interface{}
+			scanner := bufio.NewScanner(&buf)
+			for scanner.Scan() {
+				var record map[string]interface{}
+				Expect(json.Unmarshal(scanner.Bytes(), &record)).To(Succeed())
+				records = append(records, record)
+			}
+
+			Expect(records).To(HaveLen(2))
+			for _, record := range records {
+				Expect(record).To(HaveKeyWithValue("severity", "INFO"))
+				Expect(record).To(HaveKeyWithValue("severity_number", BeNumerically("==", 9)))
+				Expect(record).To(HaveKeyWithValue("body", BeAssignableToTypeOf("")))
+				Expect(record).To(HaveKey("old"))
+				Expect(record).To(HaveKey("new"))
+
+				Expect(record).To(HaveKeyWithValue("timestamp", BeAssignableToTypeOf("")))
+				_, err := time.Parse(time.RFC3339Nano, record["timestamp"].(string))
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(records[0]).To(HaveKeyWithValue("path", "/spec/replicas"))
+			Expect(records[0]).To(HaveKeyWithValue("change_kind", "modification"))
+			Expect(records[0]).To(HaveKeyWithValue("old", BeNumerically("==", 1)))
+			Expect(records[0]).To(HaveKeyWithValue("new", BeNumerically("==", 2)))
+
+			Expect(records[1]).To(HaveKeyWithValue("path", "/metadata/labels"))
+			Expect(records[1]).To(HaveKeyWithValue("change_kind", "addition"))
+			Expect(records[1]).To(HaveKeyWithValue("old", BeNil()))
+			Expect(records[1]).To(HaveKeyWithValue("new", map[string]interface{}{"app": "foobar"}))
+		})
+
+		It("should redact values at matching paths", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/data/password", dyff.MODIFICATION, "foo", "bar"),
+			}}
+
+			var buf bytes.Buffer
+			timestamp := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+			Expect((&dyff.OTelLogReport{Report: report, UseGoPatchPaths: true, Timestamp: timestamp, RedactPaths: []string{"/data/pass*"}}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(`"old":"***REDACTED***","new":"***REDACTED***"`))
+			Expect(buf.String()).ToNot(ContainSubstring("foo"))
+		})
+
+		It("should use the configured timestamp", func() {
+			var buf bytes.Buffer
+			timestamp := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+			Expect((&dyff.OTelLogReport{Report: report, UseGoPatchPaths: true, Timestamp: timestamp}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(HavePrefix(`{"timestamp":"2024-01-02T03:04:05.000000006Z","severity":"INFO","severity_number":9,"body":"modification at /spec/replicas","path":"/spec/replicas","change_kind":"modification","old":1,"new":2}`))
+		})
+	})
+})
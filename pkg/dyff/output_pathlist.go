@@ -0,0 +1,70 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"io"
+	"sort"
+
+	"github.com/gonvenience/bunt"
+)
+
+// PathListReport is a reporter that only lists the paths of the differences,
+// one path per line without any values
+type PathListReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	SortPaths       bool
+}
+
+// WriteReport writes the list of paths to the provided writer
+func (report *PathListReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	var paths []string
+	var known = map[string]struct{}{}
+	for _, diff := range report.Diffs {
+		path := pathToString(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator, showPathRoot(report.From, diff.Path))
+		if _, ok := known[path]; ok {
+			continue
+		}
+
+		known[path] = struct{}{}
+		paths = append(paths, path)
+	}
+
+	if report.SortPaths {
+		sort.SliceStable(paths, func(i, j int) bool {
+			return bunt.RemoveAllEscapeSequences(paths[i]) < bunt.RemoveAllEscapeSequences(paths[j])
+		})
+	}
+
+	for _, path := range paths {
+		_, _ = writer.WriteString(path)
+		_, _ = writer.WriteString("\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("path list report", func() {
+	Context("reporting the paths of differences", func() {
+		BeforeEach(func() {
+			SetColorSettings(OFF, OFF)
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+		})
+
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/template/image", dyff.MODIFICATION, "foo:1", "foo:2"),
+			singleDiff("/metadata/labels", dyff.ADDITION, nil, "foobar"),
+			singleDiff("/spec/template/image", dyff.MODIFICATION, "bar:1", "bar:2"),
+			singleDiff("/metadata/name", dyff.MODIFICATION, "foo", "bar"),
+		}}
+
+		writeReport := func(reportWriter dyff.ReportWriter) string {
+			var buf bytes.Buffer
+			Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should list each path once in the order of the differences", func() {
+			Expect(writeReport(&dyff.PathListReport{Report: report})).To(BeEquivalentTo(`spec.template.image
+metadata.labels
+metadata.name
+`))
+		})
+
+		It("should list the paths sorted and in Go-Patch style if configured", func() {
+			Expect(writeReport(&dyff.PathListReport{Report: report, SortPaths: true, UseGoPatchPaths: true})).To(BeEquivalentTo(`/metadata/labels
+/metadata/name
+/spec/template/image
+`))
+		})
+	})
+})
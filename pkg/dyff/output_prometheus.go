@@ -0,0 +1,61 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// PrometheusReport is a reporter that writes the number of changes in the
+// text-based exposition format, as it is read by the textfile collector of
+// the Prometheus node exporter. It writes one sample per kind of change and
+// one sample with the total number of changes.
+type PrometheusReport struct {
+	Report
+}
+
+// WriteReport writes the change metrics to the provided writer
+func (report *PrometheusReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	counts := map[rune]int{}
+	var total int
+	for _, diff := range report.Diffs {
+		for _, detail := range diff.Details {
+			counts[detail.Kind]++
+			total++
+		}
+	}
+
+	_, _ = writer.WriteString("# HELP dyff_changes_total Number of changes detected between the two inputs.\n")
+	_, _ = writer.WriteString("# TYPE dyff_changes_total gauge\n")
+
+	for _, kind := range []rune{ADDITION, REMOVAL, MODIFICATION, ORDERCHANGE, POSITIONCHANGE} {
+		_, _ = writer.WriteString(fmt.Sprintf("dyff_changes_total{kind=%q} %d\n", kindName(kind), counts[kind]))
+	}
+
+	_, _ = writer.WriteString(fmt.Sprintf("dyff_changes_total %d\n", total))
+
+	return nil
+}
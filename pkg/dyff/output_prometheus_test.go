@@ -0,0 +1,71 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("Prometheus report", func() {
+	Context("reporting the number of changes as metrics", func() {
+		writeReport := func(report dyff.Report) string {
+			var buf bytes.Buffer
+			Expect((&dyff.PrometheusReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should write one sample per kind and the total", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, "foobar"),
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("/spec/order", dyff.ORDERCHANGE, list(`[a, b]`), list(`[b, a]`)),
+				doubleDiff("/spec/list", dyff.REMOVAL, list(`[foo]`), nil, dyff.ADDITION, nil, list(`[bar]`)),
+				singleDiff("/spec/ports/0", dyff.POSITIONCHANGE, 0, 1),
+			}}
+
+			Expect(writeReport(report)).To(BeEquivalentTo(`# HELP dyff_changes_total Number of changes detected between the two inputs.
+# TYPE dyff_changes_total gauge
+dyff_changes_total{kind="addition"} 2
+dyff_changes_total{kind="removal"} 1
+dyff_changes_total{kind="modification"} 1
+dyff_changes_total{kind="order-change"} 1
+dyff_changes_total{kind="position-change"} 1
+dyff_changes_total 6
+`))
+		})
+
+		It("should only write well-formed comment and sample lines", func() {
+			sample := regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*(\{[a-zA-Z_][a-zA-Z0-9_]*="[^"]*"\})? [0-9]+$`)
+			comment := regexp.MustCompile(`^# (HELP|TYPE) dyff_changes_total .+$`)
+
+			for _, line := range strings.Split(strings.TrimSuffix(writeReport(dyff.Report{}), "\n"), "\n") {
+				Expect(sample.MatchString(line) || comment.MatchString(line)).To(BeTrue(), line)
+			}
+		})
+	})
+})
@@ -0,0 +1,88 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// statusLetters maps the detail kinds to the status letters used in the
+// status report, similar to the ones used by `git status --short`
+var statusLetters = map[rune]string{
+	ADDITION:       "A",
+	REMOVAL:        "D",
+	MODIFICATION:   "M",
+	ORDERCHANGE:    "R",
+	POSITIONCHANGE: "P",
+}
+
+// StatusReport is a reporter that prints one line per difference with a short
+// status marker followed by the path, for example `M spec.replicas`. The
+// marker consists of one letter per distinct kind of detail (A for additions,
+// D for removals, M for modifications, R for order changes, and P for position
+// changes), so that a difference with mixed kinds of details has a combined
+// marker like `DA`.
+type StatusReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+}
+
+// WriteReport writes the status lines to the provided writer
+func (report *StatusReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	markers := make([]string, len(report.Diffs))
+	var width int
+	for i, diff := range report.Diffs {
+		markers[i] = statusMarker(diff)
+		width = max(width, len(markers[i]))
+	}
+
+	for i, diff := range report.Diffs {
+		_, _ = writer.WriteString(fmt.Sprintf("%-*s %s\n",
+			width,
+			markers[i],
+			pathToString(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator, showPathRoot(report.From, diff.Path)),
+		))
+	}
+
+	return nil
+}
+
+func statusMarker(diff Diff) string {
+	var marker strings.Builder
+	for _, detail := range diff.Details {
+		letter, ok := statusLetters[detail.Kind]
+		if !ok || strings.Contains(marker.String(), letter) {
+			continue
+		}
+
+		marker.WriteString(letter)
+	}
+
+	return marker.String()
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("status report", func() {
+	Context("reporting one status line per difference", func() {
+		BeforeEach(func() {
+			SetColorSettings(OFF, OFF)
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+		})
+
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/metadata/labels", dyff.ADDITION, nil, "foobar"),
+			singleDiff("/metadata/annotations", dyff.REMOVAL, "foobar", nil),
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			singleDiff("/spec/order", dyff.ORDERCHANGE, list(`[a, b]`), list(`[b, a]`)),
+			doubleDiff("/spec/list", dyff.REMOVAL, list(`[foo]`), nil, dyff.ADDITION, nil, list(`[bar]`)),
+		}}
+
+		writeReport := func(reportWriter dyff.ReportWriter) string {
+			var buf bytes.Buffer
+			Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should write a status marker for each kind of difference", func() {
+			Expect(writeReport(&dyff.StatusReport{Report: report})).To(BeEquivalentTo(`A  metadata.labels
+D  metadata.annotations
+M  spec.replicas
+R  spec.order
+DA spec.list
+`))
+		})
+
+		It("should use Go-Patch style paths if configured", func() {
+			Expect(writeReport(&dyff.StatusReport{Report: report, UseGoPatchPaths: true})).To(BeEquivalentTo(`A  /metadata/labels
+D  /metadata/annotations
+M  /spec/replicas
+R  /spec/order
+DA /spec/list
+`))
+		})
+
+		It("should write nothing for a report without differences", func() {
+			Expect(writeReport(&dyff.StatusReport{})).To(BeEmpty())
+		})
+	})
+})
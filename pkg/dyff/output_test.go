@@ -21,11 +21,14 @@
 package dyff_test
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/gonvenience/ytbx"
+
 	"github.com/homeport/dyff/pkg/dyff"
 
 	. "github.com/gonvenience/bunt"
@@ -92,4 +95,80 @@ Miss Foobar`
                  500000`, Sprintf("Lime{#1}"), Sprintf("Blue{#2}"), Sprintf("Aqua{~#3~}"), Sprintf("LemonChiffon{_*#4*_}"))))
 		})
 	})
+
+	Context("rendering paths in different styles", func() {
+		It("should render the same path in Go-Patch, dot, and JSONPath style", func() {
+			p := path("/spec/template/containers/0/image")
+			Expect(dyff.PathString(p, dyff.GoPatchStylePaths)).To(Equal("/spec/template/containers/0/image"))
+			Expect(dyff.PathString(p, dyff.DotStylePaths)).To(Equal("spec.template.containers.0.image"))
+			Expect(dyff.PathString(p, dyff.JSONPathStylePaths)).To(Equal("$.spec.template.containers[0].image"))
+		})
+
+		It("should use bracket notation for keys with special characters and filters for named entries", func() {
+			p := path("/metadata/annotations/app.kubernetes.io")
+			Expect(dyff.PathString(p, dyff.JSONPathStylePaths)).To(Equal("$.metadata.annotations['app.kubernetes.io']"))
+
+			p = path("/spec/containers/name=it's-me/image")
+			Expect(dyff.PathString(p, dyff.GoPatchStylePaths)).To(Equal("/spec/containers/name=it's-me/image"))
+			Expect(dyff.PathString(p, dyff.JSONPathStylePaths)).To(Equal(`$.spec.containers[?(@.name=='it\'s-me')].image`))
+		})
+
+		It("should render JSONPath paths in report writers", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/containers/0/image", dyff.MODIFICATION, "foo:1", "foo:2"),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.PathListReport{Report: report, UseJSONPaths: true}).WriteReport(&buf)).To(Succeed())
+			Expect(RemoveAllEscapeSequences(buf.String())).To(Equal("$.spec.containers[0].image\n"))
+		})
+
+		It("should render dot-style paths with a custom separator and escape it in keys", func() {
+			p := &ytbx.Path{PathElements: []ytbx.PathElement{
+				{Idx: -1, Name: "metadata"},
+				{Idx: -1, Name: "annotations"},
+				{Idx: -1, Name: "example.org/a::b"},
+				{Idx: 0},
+			}}
+
+			Expect(dyff.PathString(p, dyff.DotStylePaths)).To(Equal("metadata.annotations.example.org/a::b.0"))
+			Expect(dyff.PathStringWithSeparator(p, dyff.DotStylePaths, "/")).To(Equal(`metadata/annotations/example.org\/a::b/0`))
+			Expect(dyff.PathStringWithSeparator(p, dyff.DotStylePaths, "::")).To(Equal(`metadata::annotations::example.org/a\::b::0`))
+			Expect(dyff.PathStringWithSeparator(p, dyff.GoPatchStylePaths, "::")).To(Equal(p.ToGoPatchStyle()))
+		})
+
+		It("should render dot-style paths with a custom separator in report writers", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/containers/name=a::b/image", dyff.MODIFICATION, "foo:1", "foo:2"),
+			}}
+
+			var buf bytes.Buffer
+			Expect((&dyff.PathListReport{Report: report, PathSeparator: "::"}).WriteReport(&buf)).To(Succeed())
+			Expect(RemoveAllEscapeSequences(buf.String())).To(Equal(`spec::containers::a\::b::image` + "\n"))
+
+			for _, writer := range []dyff.ReportWriter{
+				&dyff.HumanReport{Report: report, PathSeparator: "::", OmitHeader: true},
+				&dyff.StatusReport{Report: report, PathSeparator: "::"},
+				&dyff.ColorReport{Report: report, PathSeparator: "::"},
+				&dyff.ConflictMarkerReport{Report: report, PathSeparator: "::"},
+			} {
+				buf.Reset()
+				Expect(writer.WriteReport(&buf)).To(Succeed())
+				Expect(RemoveAllEscapeSequences(buf.String())).To(ContainSubstring(`spec::containers::a\::b::image`))
+			}
+
+			buf.Reset()
+			Expect((&dyff.TSVReport{Report: report, PathSeparator: "::", OmitHeader: true}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(HavePrefix(`spec::containers::a\\::b::image` + "\t"))
+
+			for _, writer := range []dyff.ReportWriter{
+				&dyff.EventStreamReport{Report: report, PathSeparator: "::"},
+				&dyff.OTelLogReport{Report: report, PathSeparator: "::"},
+			} {
+				buf.Reset()
+				Expect(writer.WriteReport(&buf)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring(`"path":"spec::containers::a\\::b::image"`))
+			}
+		})
+	})
 })
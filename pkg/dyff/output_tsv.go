@@ -0,0 +1,125 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// TSVReport is a reporter with tab-separated output, one line per detail with
+// the columns path, kind, old value, and new value. Tabs, newlines, and
+// backslashes in values are escaped so that each detail stays on one line.
+type TSVReport struct {
+	Report
+	UseGoPatchPaths bool
+	UseJSONPaths    bool
+	PathSeparator   string
+	RedactPaths     []string
+	OmitHeader      bool
+}
+
+var tsvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// WriteReport writes the tab-separated lines to the provided writer
+func (report *TSVReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	if !report.OmitHeader {
+		_, _ = writer.WriteString("PATH\tKIND\tOLD\tNEW\n")
+	}
+
+	for _, diff := range report.Diffs {
+		diff = redactDiff(diff, report.RedactPaths)
+		path := PathStringWithSeparator(diff.Path, pathStyleOf(report.UseGoPatchPaths, report.UseJSONPaths), report.PathSeparator)
+
+		for _, detail := range diff.Details {
+			from, err := tsvValue(detail.From)
+			if err != nil {
+				return err
+			}
+
+			to, err := tsvValue(detail.To)
+			if err != nil {
+				return err
+			}
+
+			_, _ = writer.WriteString(strings.Join([]string{
+				tsvEscaper.Replace(path),
+				kindName(detail.Kind),
+				from,
+				to,
+			}, "\t"))
+			_, _ = writer.WriteString("\n")
+		}
+	}
+
+	return nil
+}
+
+// kindName returns a lower-case name for the detail kind that is easier to
+// process in scripts than the symbol that is used in the human output
+func kindName(kind rune) string {
+	switch kind {
+	case ADDITION:
+		return "addition"
+
+	case REMOVAL:
+		return "removal"
+
+	case MODIFICATION:
+		return "modification"
+
+	case ORDERCHANGE:
+		return "order-change"
+
+	case POSITIONCHANGE:
+		return "position-change"
+	}
+
+	return string(kind)
+}
+
+func tsvValue(node *yamlv3.Node) (string, error) {
+	if node == nil {
+		return "", nil
+	}
+
+	if node = followAlias(node); node.Kind == yamlv3.ScalarNode {
+		return tsvEscaper.Replace(node.Value), nil
+	}
+
+	data, err := yamlv3.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+
+	return tsvEscaper.Replace(strings.TrimSuffix(string(data), "\n")), nil
+}
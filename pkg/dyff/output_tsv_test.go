@@ -0,0 +1,75 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("TSV report", func() {
+	Context("reporting differences as tab-separated values", func() {
+		writeReport := func(reportWriter dyff.ReportWriter) string {
+			var buf bytes.Buffer
+			Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should write one line per detail with a header", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/image", dyff.MODIFICATION, "foo:1", "foo:2"),
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, "foobar"),
+			}}
+
+			Expect(writeReport(&dyff.TSVReport{Report: report})).To(BeEquivalentTo(
+				"PATH\tKIND\tOLD\tNEW\n" +
+					"spec.image\tmodification\tfoo:1\tfoo:2\n" +
+					"metadata.labels\taddition\t\tfoobar\n",
+			))
+		})
+
+		It("should escape tabs and newlines in values", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/data/script", dyff.MODIFICATION, "echo\tfoo\necho bar", `C:\temp`),
+			}}
+
+			Expect(writeReport(&dyff.TSVReport{Report: report, OmitHeader: true, UseGoPatchPaths: true})).To(BeEquivalentTo(
+				"/data/script\tmodification\techo\\tfoo\\necho bar\tC:\\\\temp\n",
+			))
+		})
+
+		It("should redact values at matching paths and keep other values", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/data/password", dyff.MODIFICATION, "foo", "bar"),
+				singleDiff("/data/user", dyff.REMOVAL, "admin", nil),
+			}}
+
+			Expect(writeReport(&dyff.TSVReport{Report: report, OmitHeader: true, RedactPaths: []string{"/data/pass*"}})).To(BeEquivalentTo(
+				"data.password\tmodification\t***REDACTED***\t***REDACTED***\n" +
+					"data.user\tremoval\tadmin\t\n",
+			))
+		})
+	})
+})
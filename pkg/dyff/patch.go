@@ -0,0 +1,268 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// JSONPatchOperation is one operation of a JSON Patch (RFC 6902), where the
+// path is a JSON Pointer (RFC 6901) and the value uses plain Go types
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of the operation, which always
+// contains the value, even if it is null, unless the operation is a removal
+func (op JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{op.Op, op.Path, op.Value})
+}
+
+// UndoPatch returns the JSON Patch, which reverts the `to` document back to the
+// `from` document when it is applied to the `to` document. Lists, in which
+// entries were added, removed, or reordered, are replaced as a whole. Since a
+// JSON Patch targets a single document, input files with more than one
+// document are not supported.
+func (r Report) UndoPatch() ([]JSONPatchOperation, error) {
+	if len(r.From.Documents) > 1 || len(r.To.Documents) > 1 {
+		return nil, fmt.Errorf("failed to create undo patch, input files with multiple documents are not supported")
+	}
+
+	if len(r.Diffs) == 0 {
+		return []JSONPatchOperation{}, nil
+	}
+
+	if len(r.From.Documents) == 0 || len(r.To.Documents) == 0 {
+		return nil, fmt.Errorf("failed to create undo patch, report does not contain the input documents")
+	}
+
+	undo := undoPatch{from: r.From.Documents[0], to: documentRoot(r.To.Documents[0])}
+	for _, diff := range r.Diffs {
+		if diff.Path == nil {
+			return nil, fmt.Errorf("failed to create undo patch, additions or removals of whole documents are not supported")
+		}
+
+		for _, detail := range diff.Details {
+			if err := undo.revert(diff.Path.PathElements, detail); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return undo.operations(), nil
+}
+
+type undoPatch struct {
+	from *yamlv3.Node
+	to   *yamlv3.Node
+	ops  []JSONPatchOperation
+}
+
+func (undo *undoPatch) revert(elements []ytbx.PathElement, detail Detail) error {
+	switch detail.Kind {
+	case MODIFICATION:
+		pointer, listIdx, err := undo.pointer(elements)
+		if err != nil {
+			return err
+		}
+
+		if listIdx >= 0 {
+			return undo.replaceList(elements[:listIdx])
+		}
+
+		undo.ops = append(undo.ops, JSONPatchOperation{Op: "replace", Path: pointer, Value: nodeToValue(detail.From)})
+
+	case ADDITION, REMOVAL:
+		node := detail.To
+		if detail.Kind == REMOVAL {
+			node = detail.From
+		}
+
+		if node == nil || node.Kind != yamlv3.MappingNode {
+			return undo.replaceList(elements)
+		}
+
+		pointer, listIdx, err := undo.pointer(elements)
+		if err != nil {
+			return err
+		}
+
+		if listIdx >= 0 {
+			return undo.replaceList(elements[:listIdx])
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPointer := pointer + "/" + escapePointerToken(node.Content[i].Value)
+			if detail.Kind == ADDITION {
+				undo.ops = append(undo.ops, JSONPatchOperation{Op: "remove", Path: keyPointer})
+			} else {
+				undo.ops = append(undo.ops, JSONPatchOperation{Op: "add", Path: keyPointer, Value: nodeToValue(node.Content[i+1])})
+			}
+		}
+
+	case ORDERCHANGE:
+		return undo.replaceList(elements)
+
+	case POSITIONCHANGE:
+		// the path of a position change is the one of the moved list entry
+		return undo.replaceList(elements[:len(elements)-1])
+	}
+
+	return nil
+}
+
+// replaceList adds an operation to replace the list at the given path with
+// the respective list of the `from` document
+func (undo *undoPatch) replaceList(elements []ytbx.PathElement) error {
+	pointer, listIdx, err := undo.pointer(elements)
+	if err != nil {
+		return err
+	}
+
+	if listIdx >= 0 {
+		return undo.replaceList(elements[:listIdx])
+	}
+
+	path := ytbx.Path{PathElements: elements}
+	value, err := ytbx.Grab(undo.from, path.ToGoPatchStyle())
+	if err != nil {
+		return fmt.Errorf("failed to create undo patch, unable to look up %s: %w", path.ToGoPatchStyle(), err)
+	}
+
+	undo.ops = append(undo.ops, JSONPatchOperation{Op: "replace", Path: pointer, Value: nodeToValue(value)})
+	return nil
+}
+
+// pointer returns the JSON Pointer of the given path in the `to` document. In
+// case the path contains a list entry that cannot be addressed by its name,
+// the index of that element in the path is returned (otherwise -1), so that
+// the list can be replaced as a whole.
+func (undo *undoPatch) pointer(elements []ytbx.PathElement) (string, int, error) {
+	var pointer strings.Builder
+	node := undo.to
+
+	for i, element := range elements {
+		switch {
+		case element.Idx >= 0:
+			return "", i, nil
+
+		case element.Key != "":
+			idx := -1
+			for j, entry := range node.Content {
+				if name, ok := findValueByKey(entry, element.Key); ok && name.Value == element.Name {
+					idx = j
+					break
+				}
+			}
+
+			if idx < 0 {
+				return "", i, nil
+			}
+
+			pointer.WriteString("/" + strconv.Itoa(idx))
+			node = followAlias(node.Content[idx])
+
+		default:
+			if node == nil || node.Kind != yamlv3.MappingNode {
+				return "", -1, fmt.Errorf("failed to create undo patch, unable to find %s in the to document", ytbx.Path{PathElements: elements[:i+1]})
+			}
+
+			value, ok := findValueByKey(node, element.Name)
+			if !ok {
+				return "", -1, fmt.Errorf("failed to create undo patch, unable to find %s in the to document", ytbx.Path{PathElements: elements[:i+1]})
+			}
+
+			pointer.WriteString("/" + escapePointerToken(element.Name))
+			node = followAlias(value)
+		}
+	}
+
+	return pointer.String(), -1, nil
+}
+
+// operations returns the collected operations without those, which target a
+// location inside of a list that is replaced as a whole anyway
+func (undo *undoPatch) operations() []JSONPatchOperation {
+	var replaced []string
+	for _, op := range undo.ops {
+		if op.Op == "replace" {
+			replaced = append(replaced, op.Path)
+		}
+	}
+
+	isCovered := func(pointer string) bool {
+		for _, other := range replaced {
+			if strings.HasPrefix(pointer, other+"/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	result := []JSONPatchOperation{}
+	seen := map[string]struct{}{}
+	for _, op := range undo.ops {
+		if isCovered(op.Path) {
+			continue
+		}
+
+		if _, ok := seen[op.Op+op.Path]; ok {
+			continue
+		}
+
+		seen[op.Op+op.Path] = struct{}{}
+		result = append(result, op)
+	}
+
+	return result
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func documentRoot(node *yamlv3.Node) *yamlv3.Node {
+	if node != nil && node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+
+	return node
+}
@@ -0,0 +1,171 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+// applyJSONPatch is a minimal JSON Patch implementation for the operations
+// used by the undo patch, which is sufficient to verify the round-trip
+func applyJSONPatch(document interface{}, operations []dyff.JSONPatchOperation) interface{} {
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+
+	for _, op := range operations {
+		if op.Path == "" {
+			document = op.Value
+			continue
+		}
+
+		tokens := strings.Split(op.Path, "/")[1:]
+		parent := document
+		for _, token := range tokens[:len(tokens)-1] {
+			switch typed := parent.(type) {
+			case map[string]interface{}:
+				parent = typed[unescape.Replace(token)]
+
+			case []interface{}:
+				idx, err := strconv.Atoi(token)
+				Expect(err).ToNot(HaveOccurred())
+				parent = typed[idx]
+			}
+		}
+
+		last := unescape.Replace(tokens[len(tokens)-1])
+		switch typed := parent.(type) {
+		case map[string]interface{}:
+			if op.Op == "remove" {
+				delete(typed, last)
+			} else {
+				typed[last] = op.Value
+			}
+
+		case []interface{}:
+			idx, err := strconv.Atoi(last)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(op.Op).To(Equal("replace"))
+			typed[idx] = op.Value
+		}
+	}
+
+	return document
+}
+
+var _ = Describe("undo patch", func() {
+	value := func(input string) interface{} {
+		var result interface{}
+		Expect(yamlv3.Unmarshal([]byte(input), &result)).To(Succeed())
+		return result
+	}
+
+	Context("creating the patch to revert the changes", func() {
+		It("should reproduce the from document when applied to the to document", func() {
+			from := `---
+name: foo
+version: 1
+labels:
+  app/name: foo
+  removed: label
+containers:
+- name: app
+  image: app:1.0
+  args: [a, b]
+- name: sidecar
+  image: sidecar:1.0
+ports: [80, 443]
+`
+
+			to := `---
+name: bar
+version: 2
+labels:
+  app/name: foo
+  added: label
+containers:
+- name: sidecar
+  image: sidecar:2.0
+- name: app
+  image: app:1.0
+  args: [a, c]
+ports: [443, 80, 8080]
+`
+
+			report, err := dyff.CompareBytes([]byte(from), []byte(to))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).ToNot(BeEmpty())
+
+			undo, err := report.UndoPatch()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applyJSONPatch(value(to), undo)).To(Equal(value(from)))
+		})
+
+		It("should use JSON Pointers with escaped keys and list indices", func() {
+			report, err := dyff.CompareBytes(
+				[]byte("labels: {app/name: foo}\nlist: [{name: x, value: 1}]\n"),
+				[]byte("labels: {app/name: bar}\nlist: [{name: x, value: 2}]\n"),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			undo, err := report.UndoPatch()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(undo).To(Equal([]dyff.JSONPatchOperation{
+				{Op: "replace", Path: "/labels/app~1name", Value: "foo"},
+				{Op: "replace", Path: "/list/0/value", Value: 1},
+			}))
+		})
+
+		It("should keep null values when the patch is marshalled to JSON", func() {
+			from := "foo: null\nbar: baz\n"
+			to := "foo: bar\n"
+
+			report, err := dyff.CompareBytes([]byte(from), []byte(to))
+			Expect(err).ToNot(HaveOccurred())
+
+			undo, err := report.UndoPatch()
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := json.Marshal(undo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`{"op":"replace","path":"/foo","value":null}`))
+
+			var operations []dyff.JSONPatchOperation
+			Expect(json.Unmarshal(data, &operations)).To(Succeed())
+			Expect(applyJSONPatch(value(to), operations)).To(Equal(value(from)))
+		})
+
+		It("should fail for input files with multiple documents", func() {
+			report, err := dyff.CompareBytes([]byte("---\nfoo: bar\n---\nfoo: bar\n"), []byte("---\nfoo: baz\n---\nfoo: bar\n"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = report.UndoPatch()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
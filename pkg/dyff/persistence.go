@@ -0,0 +1,241 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type persistedReport struct {
+	From     persistedInputFile `json:"from"`
+	To       persistedInputFile `json:"to"`
+	Diffs    []persistedDiff    `json:"diffs"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+type persistedInputFile struct {
+	Location  string           `json:"location,omitempty"`
+	Note      string           `json:"note,omitempty"`
+	Names     []string         `json:"names,omitempty"`
+	Documents []*persistedNode `json:"documents,omitempty"`
+}
+
+type persistedDiff struct {
+	Path    *persistedPath    `json:"path,omitempty"`
+	Details []persistedDetail `json:"details"`
+	Note    string            `json:"note,omitempty"`
+	Source  string            `json:"source,omitempty"`
+}
+
+type persistedPath struct {
+	DocumentIdx  int                `json:"documentIdx"`
+	PathElements []ytbx.PathElement `json:"pathElements"`
+}
+
+type persistedDetail struct {
+	Kind string         `json:"kind"`
+	From *persistedNode `json:"from,omitempty"`
+	To   *persistedNode `json:"to,omitempty"`
+}
+
+type persistedNode struct {
+	Kind        yamlv3.Kind      `json:"kind"`
+	Style       yamlv3.Style     `json:"style,omitempty"`
+	Tag         string           `json:"tag,omitempty"`
+	Value       string           `json:"value,omitempty"`
+	Anchor      string           `json:"anchor,omitempty"`
+	Alias       *persistedNode   `json:"alias,omitempty"`
+	Content     []*persistedNode `json:"content,omitempty"`
+	HeadComment string           `json:"headComment,omitempty"`
+	LineComment string           `json:"lineComment,omitempty"`
+	FootComment string           `json:"footComment,omitempty"`
+	Line        int              `json:"line,omitempty"`
+	Column      int              `json:"column,omitempty"`
+}
+
+// SaveReport writes the report including the input documents in a JSON based
+// format to the provided writer, so that it can be restored using LoadReport
+// without the need to compare the input files again
+func SaveReport(out io.Writer, report Report) error {
+	persisted := persistedReport{
+		From:     persistInputFile(report.From),
+		To:       persistInputFile(report.To),
+		Warnings: report.Warnings,
+	}
+
+	for _, diff := range report.Diffs {
+		persistedDiff := persistedDiff{
+			Details: []persistedDetail{},
+			Note:    diff.Note,
+			Source:  diff.Source,
+		}
+
+		if diff.Path != nil {
+			persistedDiff.Path = &persistedPath{
+				DocumentIdx:  diff.Path.DocumentIdx,
+				PathElements: diff.Path.PathElements,
+			}
+		}
+
+		for _, detail := range diff.Details {
+			persistedDiff.Details = append(persistedDiff.Details, persistedDetail{
+				Kind: string(detail.Kind),
+				From: persistNode(detail.From),
+				To:   persistNode(detail.To),
+			})
+		}
+
+		persisted.Diffs = append(persisted.Diffs, persistedDiff)
+	}
+
+	return json.NewEncoder(out).Encode(persisted)
+}
+
+// LoadReport reads a report that was written using SaveReport
+func LoadReport(in io.Reader) (Report, error) {
+	var persisted persistedReport
+	if err := json.NewDecoder(in).Decode(&persisted); err != nil {
+		return Report{}, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	from := restoreInputFile(persisted.From)
+	to := restoreInputFile(persisted.To)
+
+	var diffs []Diff
+	for _, persistedDiff := range persisted.Diffs {
+		diff := Diff{
+			Details: []Detail{},
+			Note:    persistedDiff.Note,
+			Source:  persistedDiff.Source,
+		}
+
+		if persistedDiff.Path != nil {
+			diff.Path = &ytbx.Path{
+				Root:         &from,
+				DocumentIdx:  persistedDiff.Path.DocumentIdx,
+				PathElements: persistedDiff.Path.PathElements,
+			}
+		}
+
+		for _, persistedDetail := range persistedDiff.Details {
+			kind, size := utf8.DecodeRuneInString(persistedDetail.Kind)
+			if size == 0 || size != len(persistedDetail.Kind) {
+				return Report{}, fmt.Errorf("failed to decode report, invalid detail kind %q", persistedDetail.Kind)
+			}
+
+			diff.Details = append(diff.Details, Detail{
+				Kind: kind,
+				From: restoreNode(persistedDetail.From),
+				To:   restoreNode(persistedDetail.To),
+			})
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return Report{From: from, To: to, Diffs: diffs, Warnings: persisted.Warnings}, nil
+}
+
+func persistInputFile(inputFile ytbx.InputFile) persistedInputFile {
+	result := persistedInputFile{
+		Location: inputFile.Location,
+		Note:     inputFile.Note,
+		Names:    inputFile.Names,
+	}
+
+	for _, document := range inputFile.Documents {
+		result.Documents = append(result.Documents, persistNode(document))
+	}
+
+	return result
+}
+
+func restoreInputFile(inputFile persistedInputFile) ytbx.InputFile {
+	result := ytbx.InputFile{
+		Location: inputFile.Location,
+		Note:     inputFile.Note,
+		Names:    inputFile.Names,
+	}
+
+	for _, document := range inputFile.Documents {
+		result.Documents = append(result.Documents, restoreNode(document))
+	}
+
+	return result
+}
+
+func persistNode(node *yamlv3.Node) *persistedNode {
+	if node == nil {
+		return nil
+	}
+
+	result := &persistedNode{
+		Kind:        node.Kind,
+		Style:       node.Style,
+		Tag:         node.Tag,
+		Value:       node.Value,
+		Anchor:      node.Anchor,
+		Alias:       persistNode(node.Alias),
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+		Line:        node.Line,
+		Column:      node.Column,
+	}
+
+	for _, entry := range node.Content {
+		result.Content = append(result.Content, persistNode(entry))
+	}
+
+	return result
+}
+
+func restoreNode(node *persistedNode) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	result := &yamlv3.Node{
+		Kind:        node.Kind,
+		Style:       node.Style,
+		Tag:         node.Tag,
+		Value:       node.Value,
+		Anchor:      node.Anchor,
+		Alias:       restoreNode(node.Alias),
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+		Line:        node.Line,
+		Column:      node.Column,
+	}
+
+	for _, entry := range node.Content {
+		result.Content = append(result.Content, restoreNode(entry))
+	}
+
+	return result
+}
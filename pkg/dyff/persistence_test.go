@@ -0,0 +1,89 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("report persistence", func() {
+	Context("saving and loading reports", func() {
+		BeforeEach(func() {
+			SetColorSettings(ON, ON)
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+		})
+
+		render := func(report dyff.Report) string {
+			var buf bytes.Buffer
+			for _, reportWriter := range []dyff.ReportWriter{
+				&dyff.HumanReport{Report: report},
+				&dyff.HumanReport{Report: report, UseGoPatchPaths: true, NoTableStyle: true},
+				&dyff.BriefReport{Report: report},
+				&dyff.GroupedReport{HumanReport: dyff.HumanReport{Report: report}},
+				&dyff.PathListReport{Report: report},
+			} {
+				Expect(reportWriter.WriteReport(&buf)).To(Succeed())
+			}
+
+			return buf.String()
+		}
+
+		roundTrip := func(fromPath string, toPath string) {
+			from, to := loadFiles(fromPath, toPath)
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).ToNot(BeEmpty())
+
+			var buf bytes.Buffer
+			Expect(dyff.SaveReport(&buf, report)).To(Succeed())
+
+			loaded, err := dyff.LoadReport(&buf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.Diffs).To(HaveLen(len(report.Diffs)))
+
+			Expect(render(loaded)).To(Equal(render(report)))
+		}
+
+		It("should render a loaded report identical to the original report", func() {
+			roundTrip(assets("testbed", "from.yml"), assets("testbed", "to.yml"))
+		})
+
+		It("should render a loaded report with multiple documents identical to the original report", func() {
+			roundTrip(assets("kubernetes-yaml", "from.yml"), assets("kubernetes-yaml", "to.yml"))
+		})
+
+		It("should fail to load invalid input", func() {
+			_, err := dyff.LoadReport(bytes.NewBufferString("{"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	pathpkg "path"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// RedactedValue is the text that replaces redacted values in the output
+const RedactedValue = "***REDACTED***"
+
+// redactDiff returns a copy of the difference, where all values located at a
+// path that matches one of the provided Go-Patch style path patterns are
+// replaced with a redacted placeholder, where `*` matches any single path
+// element. The path and the kind of each detail are kept as-is. Differences
+// without a path (added or removed documents) are redacted per document, where
+// the paths are relative to the root of each document.
+func redactDiff(diff Diff, patterns []string) Diff {
+	if len(patterns) == 0 {
+		return diff
+	}
+
+	var path ytbx.Path
+	if diff.Path != nil {
+		path = *diff.Path
+	}
+
+	details := make([]Detail, len(diff.Details))
+	for i, detail := range diff.Details {
+		details[i] = Detail{
+			Kind: detail.Kind,
+			From: redactNode(path, detail.From, patterns),
+			To:   redactNode(path, detail.To, patterns),
+		}
+	}
+
+	diff.Details = details
+	return diff
+}
+
+func redactNode(path ytbx.Path, node *yamlv3.Node, patterns []string) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	pathString := path.String()
+	for _, pattern := range patterns {
+		if ok, err := pathpkg.Match(pattern, pathString); err == nil && ok {
+			return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: RedactedValue}
+		}
+	}
+
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		result := *node
+		result.Content = make([]*yamlv3.Node, len(node.Content))
+		for i, document := range node.Content {
+			result.Content[i] = redactNode(ytbx.Path{DocumentIdx: path.DocumentIdx}, document, patterns)
+		}
+
+		return &result
+
+	case yamlv3.MappingNode:
+		result := *node
+		result.Content = make([]*yamlv3.Node, len(node.Content))
+		for i := 0; i < len(node.Content); i += 2 {
+			result.Content[i] = node.Content[i]
+			result.Content[i+1] = redactNode(ytbx.NewPathWithNamedElement(path, node.Content[i].Value), node.Content[i+1], patterns)
+		}
+
+		return &result
+
+	case yamlv3.SequenceNode:
+		result := *node
+		result.Content = make([]*yamlv3.Node, len(node.Content))
+		for i, entry := range node.Content {
+			result.Content[i] = redactNode(ytbx.NewPathWithIndexedListElement(path, i), entry, patterns)
+		}
+
+		return &result
+	}
+
+	return node
+}
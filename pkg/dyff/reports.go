@@ -1,15 +1,21 @@
 package dyff
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 func (r Report) filter(hasPath func(*ytbx.Path) bool) (result Report) {
 	result = Report{
-		From: r.From,
-		To:   r.To,
+		From:     r.From,
+		To:       r.To,
+		Warnings: r.Warnings,
 	}
 
 	for _, diff := range r.Diffs {
@@ -21,6 +27,136 @@ func (r Report) filter(hasPath func(*ytbx.Path) bool) (result Report) {
 	return result
 }
 
+// filterByKind returns a new report with the differences of documents, whose
+// Kubernetes resource kind is accepted, where documents without a kind have an
+// empty kind. Since the path of a difference refers to the index of the from
+// document, which may differ from the index of the matching to document, only
+// the from document is checked.
+func (r Report) filterByKind(accept func(kind string) bool) (result Report) {
+	result = Report{
+		From:     r.From,
+		To:       r.To,
+		Warnings: r.Warnings,
+	}
+
+	acceptedAt := func(idx int) bool {
+		return idx >= 0 && idx < len(r.From.Documents) && accept(documentKind(r.From.Documents[idx]))
+	}
+
+	// differences without a path are additions or removals of whole documents,
+	// which are reduced to the documents that are accepted
+	acceptedDocuments := func(node *yamlv3.Node) *yamlv3.Node {
+		if node == nil || node.Kind != yamlv3.DocumentNode {
+			return nil
+		}
+
+		result := *node
+		result.Content = nil
+		for _, document := range node.Content {
+			if accept(documentKind(document)) {
+				result.Content = append(result.Content, document)
+			}
+		}
+
+		if len(result.Content) == 0 {
+			return nil
+		}
+
+		return &result
+	}
+
+	for _, diff := range r.Diffs {
+		if diff.Path != nil {
+			if acceptedAt(diff.Path.DocumentIdx) {
+				result.Diffs = append(result.Diffs, diff)
+			}
+
+			continue
+		}
+
+		var details []Detail
+		for _, detail := range diff.Details {
+			from, to := acceptedDocuments(detail.From), acceptedDocuments(detail.To)
+			if from != nil || to != nil {
+				details = append(details, Detail{Kind: detail.Kind, From: from, To: to})
+			}
+		}
+
+		if len(details) > 0 {
+			diff.Details = details
+			result.Diffs = append(result.Diffs, diff)
+		}
+	}
+
+	return result
+}
+
+// limitPerDocument returns a new report with at most the given number of
+// differences per document and a warning for each document that has more.
+// Differences without a path (whole documents) are not limited.
+func (r Report) limitPerDocument(limit int) Report {
+	result := Report{
+		From:     r.From,
+		To:       r.To,
+		Warnings: r.Warnings,
+	}
+
+	var order []int
+	counts := map[int]int{}
+	for _, diff := range r.Diffs {
+		if diff.Path == nil {
+			result.Diffs = append(result.Diffs, diff)
+			continue
+		}
+
+		idx := diff.Path.DocumentIdx
+		if _, ok := counts[idx]; !ok {
+			order = append(order, idx)
+		}
+
+		counts[idx]++
+		if counts[idx] <= limit {
+			result.Diffs = append(result.Diffs, diff)
+		}
+	}
+
+	for _, idx := range order {
+		if counts[idx] > limit {
+			name := fmt.Sprintf("document #%d", idx+1)
+			if idx < len(r.From.Names) {
+				name = fmt.Sprintf("%s (%s)", name, r.From.Names[idx])
+			}
+
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s has %d differences, only the first %d are reported",
+				name, counts[idx], limit))
+		}
+	}
+
+	return result
+}
+
+// documentKind returns the value of the `kind` field of the document, or an
+// empty string if there is none
+func documentKind(node *yamlv3.Node) string {
+	if node == nil {
+		return ""
+	}
+
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	if node.Kind != yamlv3.MappingNode {
+		return ""
+	}
+
+	if kind, found := findValueByKey(node, "kind"); found && kind.Kind == yamlv3.ScalarNode {
+		return kind.Value
+	}
+
+	return ""
+}
+
 // Filter accepts YAML paths as input and returns a new report with differences for those paths only
 func (r Report) Filter(paths ...string) (result Report) {
 	if len(paths) == 0 {
@@ -57,7 +193,8 @@ func (r Report) Exclude(paths ...string) (result Report) {
 	})
 }
 
-// FilterRegexp accepts regular expressions as input and returns a new report with differences for matching those patterns
+// FilterRegexp accepts regular expressions as input and returns a new report with differences for matching those patterns.
+// Differences without a path (i.e. the addition or removal of whole documents) never match, see FilterRegexpNilAsEmpty.
 func (r Report) FilterRegexp(pattern ...string) (result Report) {
 	if len(pattern) == 0 {
 		return r
@@ -78,7 +215,163 @@ func (r Report) FilterRegexp(pattern ...string) (result Report) {
 	})
 }
 
-// ExcludeRegexp accepts regular expressions as input and returns a new report with differences for not matching those patterns
+// FilterRegexpNilAsEmpty works like FilterRegexp, but matches differences without a path (i.e. the addition or removal
+// of whole documents) as an empty string, so that they can be targeted using a pattern like `^$`.
+func (r Report) FilterRegexpNilAsEmpty(pattern ...string) (result Report) {
+	if len(pattern) == 0 {
+		return r
+	}
+
+	regexps := make([]*regexp.Regexp, len(pattern))
+	for i := range pattern {
+		regexps[i] = regexp.MustCompile(pattern[i])
+	}
+
+	return r.filter(func(filterPath *ytbx.Path) bool {
+		for _, regexp := range regexps {
+			if regexp.MatchString(regexpPathString(filterPath)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterRegexpSubtrees works like FilterRegexp, but also looks into the
+// entries that were added or removed: an addition or removal at a path that
+// does not match is kept with only those entries, which contain a path that
+// matches one of the patterns. This way, adding `hostNetwork: true` to a pod
+// spec is kept when filtering for `/hostNetwork$`, although the difference
+// itself is reported at the path of the pod spec.
+func (r Report) FilterRegexpSubtrees(pattern ...string) (result Report) {
+	if len(pattern) == 0 {
+		return r
+	}
+
+	regexps := make([]*regexp.Regexp, len(pattern))
+	for i := range pattern {
+		regexps[i] = regexp.MustCompile(pattern[i])
+	}
+
+	matches := func(path string) bool {
+		for _, regexp := range regexps {
+			if regexp.MatchString(path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result = Report{
+		From:     r.From,
+		To:       r.To,
+		Warnings: r.Warnings,
+	}
+
+	for _, diff := range r.Diffs {
+		if diff.Path != nil && matches(diff.Path.String()) {
+			result.Diffs = append(result.Diffs, diff)
+			continue
+		}
+
+		var path ytbx.Path
+		if diff.Path != nil {
+			path = *diff.Path
+		}
+
+		var details []Detail
+		for _, detail := range diff.Details {
+			switch detail.Kind {
+			case ADDITION:
+				if node := matchingEntries(path, detail.To, matches); node != nil {
+					details = append(details, Detail{Kind: ADDITION, To: node})
+				}
+
+			case REMOVAL:
+				if node := matchingEntries(path, detail.From, matches); node != nil {
+					details = append(details, Detail{Kind: REMOVAL, From: node})
+				}
+			}
+		}
+
+		if len(details) > 0 {
+			result.Diffs = append(result.Diffs, Diff{Path: diff.Path, Details: details})
+		}
+	}
+
+	return result
+}
+
+// matchingEntries returns a copy of the given map, list, or documents node
+// with only those entries, which contain a path that matches, or nil if there
+// are none.
+func matchingEntries(path ytbx.Path, node *yamlv3.Node, matches func(string) bool) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	result := *node
+	result.Content = nil
+
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, document := range node.Content {
+			if containsMatchingPath(ytbx.Path{DocumentIdx: path.DocumentIdx}, document, matches) {
+				result.Content = append(result.Content, document)
+			}
+		}
+
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if containsMatchingPath(ytbx.NewPathWithNamedElement(path, node.Content[i].Value), node.Content[i+1], matches) {
+				result.Content = append(result.Content, node.Content[i], node.Content[i+1])
+			}
+		}
+
+	case yamlv3.SequenceNode:
+		for i, entry := range node.Content {
+			if containsMatchingPath(ytbx.NewPathWithIndexedListElement(path, i), entry, matches) {
+				result.Content = append(result.Content, entry)
+			}
+		}
+	}
+
+	if len(result.Content) == 0 {
+		return nil
+	}
+
+	return &result
+}
+
+// containsMatchingPath checks whether the path itself, or any path inside the
+// node, matches
+func containsMatchingPath(path ytbx.Path, node *yamlv3.Node, matches func(string) bool) bool {
+	if matches(path.String()) {
+		return true
+	}
+
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if containsMatchingPath(ytbx.NewPathWithNamedElement(path, node.Content[i].Value), node.Content[i+1], matches) {
+				return true
+			}
+		}
+
+	case yamlv3.SequenceNode:
+		for i, entry := range node.Content {
+			if containsMatchingPath(ytbx.NewPathWithIndexedListElement(path, i), entry, matches) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ExcludeRegexp accepts regular expressions as input and returns a new report with differences for not matching those patterns.
+// Differences without a path (i.e. the addition or removal of whole documents) never match and are therefore kept, see
+// ExcludeRegexpNilAsEmpty.
 func (r Report) ExcludeRegexp(pattern ...string) (result Report) {
 	if len(pattern) == 0 {
 		return r
@@ -98,3 +391,777 @@ func (r Report) ExcludeRegexp(pattern ...string) (result Report) {
 		return true
 	})
 }
+
+// ExcludeRegexpNilAsEmpty works like ExcludeRegexp, but matches differences without a path (i.e. the addition or
+// removal of whole documents) as an empty string, so that they can be targeted using a pattern like `^$`.
+func (r Report) ExcludeRegexpNilAsEmpty(pattern ...string) (result Report) {
+	if len(pattern) == 0 {
+		return r
+	}
+
+	regexps := make([]*regexp.Regexp, len(pattern))
+	for i := range pattern {
+		regexps[i] = regexp.MustCompile(pattern[i])
+	}
+
+	return r.filter(func(filterPath *ytbx.Path) bool {
+		for _, regexp := range regexps {
+			if regexp.MatchString(regexpPathString(filterPath)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// SecurityDefaults returns regular expressions for FilterRegexpSubtrees, which
+// match the paths of security-relevant Kubernetes fields: security contexts
+// (with for example `privileged` and `capabilities`), the use of host
+// namespaces and host paths, service account settings, as well as the rules,
+// subjects, and role references of RBAC resources. Use it like this to review
+// only those changes: `report.FilterRegexpSubtrees(dyff.SecurityDefaults()...)`
+func SecurityDefaults() []string {
+	return []string{
+		`/securityContext(/|$)`,
+		`/(privileged|allowPrivilegeEscalation|capabilities)(/|$)`,
+		`/(hostNetwork|hostPID|hostIPC|hostPath|hostPort)(/|$)`,
+		`/(serviceAccountName|serviceAccount|automountServiceAccountToken)$`,
+		`^/(rules|subjects|roleRef|aggregationRule)(/|$)`,
+	}
+}
+
+// FilterByValue returns a new report with the differences, where at least one
+// detail has values that satisfy the predicate. The from or to value passed to
+// the predicate is nil for additions or removals respectively.
+func (r Report) FilterByValue(pred func(from *yamlv3.Node, to *yamlv3.Node) bool) (result Report) {
+	result = Report{
+		From:     r.From,
+		To:       r.To,
+		Warnings: r.Warnings,
+	}
+
+	for _, diff := range r.Diffs {
+		for _, detail := range diff.Details {
+			if pred(detail.From, detail.To) {
+				result.Diffs = append(result.Diffs, diff)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// regexpPathString returns the string representation of the path that is used
+// to match regular expressions, which is an empty string for nil paths
+func regexpPathString(path *ytbx.Path) string {
+	if path == nil {
+		return ""
+	}
+
+	return path.String()
+}
+
+// Partition splits the report into two reports, one with the major and one with
+// the minor differences. A difference is considered minor if all of its details
+// are string modifications with a change ratio below the provided threshold
+// (see HumanReport MinorChangeThreshold), all other differences are major.
+func (r Report) Partition(threshold float64) (major Report, minor Report) {
+	major = Report{From: r.From, To: r.To, Warnings: r.Warnings}
+	minor = Report{From: r.From, To: r.To, Warnings: r.Warnings}
+
+	for _, diff := range r.Diffs {
+		if isMinorDiff(diff, threshold) {
+			minor.Diffs = append(minor.Diffs, diff)
+		} else {
+			major.Diffs = append(major.Diffs, diff)
+		}
+	}
+
+	return major, minor
+}
+
+func isMinorDiff(diff Diff, threshold float64) bool {
+	if len(diff.Details) == 0 {
+		return false
+	}
+
+	for _, detail := range diff.Details {
+		if detail.Kind != MODIFICATION || detail.From == nil || detail.To == nil {
+			return false
+		}
+
+		if humanReadableType(detail.From) != "string" || humanReadableType(detail.To) != "string" {
+			return false
+		}
+
+		if !isMinorChange(detail.From.Value, detail.To.Value, threshold) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// documentPathPrefix matches a path string with a document index prefix, for
+// example `#1/spec/replicas` or `#1.spec.replicas`
+var documentPathPrefix = regexp.MustCompile(`^#(\d+)(?:\.|(/))(.*)$`)
+
+// Annotate accepts a mapping of YAML paths to notes and returns a new report,
+// where each difference with a matching path has the respective note attached.
+// A path with a document index prefix like `#1/spec/replicas` only matches the
+// differences in that document (counting from zero) and takes precedence over
+// a path without prefix, which matches the differences in all documents.
+func (r Report) Annotate(labels map[string]string) Report {
+	notes := make(map[string]string, len(labels))
+	documentNotes := map[string]string{}
+	for pathString, note := range labels {
+		if captures := documentPathPrefix.FindStringSubmatch(pathString); captures != nil {
+			idx, err := strconv.Atoi(captures[1])
+			if err != nil {
+				continue
+			}
+
+			if path, err := ytbx.ParsePathStringUnsafe(captures[2] + captures[3]); err == nil {
+				path.DocumentIdx = idx
+				documentNotes[documentPathKey(path)] = note
+			}
+
+			continue
+		}
+
+		if path, err := ytbx.ParsePathStringUnsafe(pathString); err == nil {
+			notes[path.String()] = note
+		}
+	}
+
+	result := Report{From: r.From, To: r.To, Warnings: r.Warnings}
+	for _, diff := range r.Diffs {
+		if diff.Path != nil {
+			if note, ok := documentNotes[documentPathKey(*diff.Path)]; ok {
+				diff.Note = note
+			} else if note, ok := notes[diff.Path.String()]; ok {
+				diff.Note = note
+			}
+		}
+
+		result.Diffs = append(result.Diffs, diff)
+	}
+
+	return result
+}
+
+// MapDetails returns a new report, where the provided function is applied to
+// each detail of each difference. A detail is kept (in its returned form) if
+// the function returns true, and dropped otherwise. Differences without any
+// remaining details are removed from the report.
+func (r Report) MapDetails(fn func(Diff, Detail) (Detail, bool)) Report {
+	result := Report{From: r.From, To: r.To, Warnings: r.Warnings}
+	for _, diff := range r.Diffs {
+		var details []Detail
+		for _, detail := range diff.Details {
+			if mapped, keep := fn(diff, detail); keep {
+				details = append(details, mapped)
+			}
+		}
+
+		if len(details) > 0 {
+			diff.Details = details
+			result.Diffs = append(result.Diffs, diff)
+		}
+	}
+
+	return result
+}
+
+// DedupeSubtrees returns a new report without additions and removals below a
+// path that is already reported as added or removed, for example the addition
+// of `/spec/foo/bar` in case `/spec/foo` is reported as added. In a merged
+// report, only differences of the same source are considered duplicates.
+// Differences without any remaining details are removed from the report.
+func (r Report) DedupeSubtrees() Report {
+	added, removed := map[string]struct{}{}, map[string]struct{}{}
+	for _, diff := range r.Diffs {
+		if diff.Path == nil {
+			continue
+		}
+
+		for _, detail := range diff.Details {
+			for _, path := range subtreePaths(diff.Source, *diff.Path, detail, ADDITION, detail.From, detail.To) {
+				added[path] = struct{}{}
+			}
+
+			for _, path := range subtreePaths(diff.Source, *diff.Path, detail, REMOVAL, detail.To, detail.From) {
+				removed[path] = struct{}{}
+			}
+		}
+	}
+
+	// the map entries of an addition (removal) are located below the path of
+	// the difference, so additions (removals) at one of those exact paths are
+	// duplicates as well, whereas a modification is located at the path itself
+	isBelow := func(paths map[string]struct{}, source string, path ytbx.Path, detail Detail) bool {
+		if detail.Kind != MODIFICATION {
+			if _, ok := paths[sourcePathKey(source, path)]; ok {
+				return true
+			}
+		}
+
+		for len(path.PathElements) > 0 {
+			path.PathElements = path.PathElements[:len(path.PathElements)-1]
+			if _, ok := paths[sourcePathKey(source, path)]; ok {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return r.MapDetails(func(diff Diff, detail Detail) (Detail, bool) {
+		if diff.Path == nil {
+			return detail, true
+		}
+
+		switch {
+		case detail.Kind == ADDITION || (detail.Kind == MODIFICATION && detail.From == nil):
+			return detail, !isBelow(added, diff.Source, *diff.Path, detail)
+
+		case detail.Kind == REMOVAL || (detail.Kind == MODIFICATION && detail.To == nil):
+			return detail, !isBelow(removed, diff.Source, *diff.Path, detail)
+		}
+
+		return detail, true
+	})
+}
+
+// subtreePaths returns the paths of the subtrees that the detail adds (or
+// removes, depending on the kind), which are the paths of the map entries of
+// an addition (removal) of map entries, or the path itself in case the detail
+// is a modification from (to) nothing
+func subtreePaths(source string, path ytbx.Path, detail Detail, kind rune, missing *yamlv3.Node, present *yamlv3.Node) []string {
+	switch {
+	case detail.Kind == MODIFICATION && missing == nil && present != nil:
+		return []string{sourcePathKey(source, path)}
+
+	case detail.Kind == kind && present != nil && present.Kind == yamlv3.MappingNode:
+		var result []string
+		for i := 0; i+1 < len(present.Content); i += 2 {
+			result = append(result, sourcePathKey(source, ytbx.NewPathWithNamedElement(path, present.Content[i].Value)))
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// documentPathKey returns a string that identifies the path including the
+// index of the document it belongs to
+func documentPathKey(path ytbx.Path) string {
+	return fmt.Sprintf("#%d%s", path.DocumentIdx, path.String())
+}
+
+// sourcePathKey returns a string that identifies the path like documentPathKey,
+// but also includes the source of the difference (e.g. the file name in a
+// merged report)
+func sourcePathKey(source string, path ytbx.Path) string {
+	return fmt.Sprintf("%q%s", source, documentPathKey(path))
+}
+
+// Prune returns a tidied up report, which no longer contains details without
+// any content (i.e. additions or removals of empty maps or lists, as well as
+// modifications and order changes without values) and no longer contains
+// differences that have no details left. It is meant to be used after filters
+// or transformations that could leave such empty entries behind.
+func (r Report) Prune() Report {
+	isEmpty := func(node *yamlv3.Node) bool {
+		if node == nil {
+			return true
+		}
+
+		switch node.Kind {
+		case yamlv3.MappingNode, yamlv3.SequenceNode, yamlv3.DocumentNode:
+			return len(node.Content) == 0
+		}
+
+		return false
+	}
+
+	return r.MapDetails(func(_ Diff, detail Detail) (Detail, bool) {
+		switch detail.Kind {
+		case ADDITION:
+			return detail, !isEmpty(detail.To)
+
+		case REMOVAL:
+			return detail, !isEmpty(detail.From)
+		}
+
+		return detail, detail.From != nil || detail.To != nil
+	})
+}
+
+// MergeReportsWithSource merges the differences of the named reports into one
+// report, where each difference has its source set to the name of the report
+// it originates from. The reports are merged in the order of their names.
+func MergeReportsWithSource(named map[string]Report) Report {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var result Report
+	for _, name := range names {
+		for _, diff := range named[name].Diffs {
+			diff.Source = name
+			result.Diffs = append(result.Diffs, diff)
+		}
+
+		for _, warning := range named[name].Warnings {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", name, warning))
+		}
+	}
+
+	return result
+}
+
+// Records returns one change record per detail of each difference, where the
+// path is in go-patch style, the kind is one of `addition`, `removal`,
+// `modification`, `order-change`, or `position-change`, and the values are
+// plain Go types. The addition or removal of multiple whole documents results
+// in one record per document.
+func (r Report) Records() []ChangeRecord {
+	var records []ChangeRecord
+	for _, diff := range r.Diffs {
+		path := PathString(diff.Path, GoPatchStylePaths)
+		for _, detail := range diff.Details {
+			switch {
+			case detail.Kind == ADDITION && isMultiDocument(detail.To):
+				for _, document := range detail.To.Content {
+					records = append(records, ChangeRecord{Path: path, Kind: kindName(detail.Kind), To: nodeToValue(document)})
+				}
+
+			case detail.Kind == REMOVAL && isMultiDocument(detail.From):
+				for _, document := range detail.From.Content {
+					records = append(records, ChangeRecord{Path: path, Kind: kindName(detail.Kind), From: nodeToValue(document)})
+				}
+
+			default:
+				records = append(records, ChangeRecord{
+					Path: path,
+					Kind: kindName(detail.Kind),
+					From: nodeToValue(detail.From),
+					To:   nodeToValue(detail.To),
+				})
+			}
+		}
+	}
+
+	return records
+}
+
+// isMultiDocument returns whether the node is a document node that wraps more
+// than one document, which is how whole document additions and removals are
+// reported
+func isMultiDocument(node *yamlv3.Node) bool {
+	return node != nil && node.Kind == yamlv3.DocumentNode && len(node.Content) > 1
+}
+
+// EachDetail calls the given function for every detail of each difference in
+// the order of the differences and their details, iteration stops with the
+// first error returned by the function and that error is returned as-is
+func (r Report) EachDetail(fn func(path *ytbx.Path, detail Detail) error) error {
+	for _, diff := range r.Diffs {
+		for _, detail := range diff.Details {
+			if err := fn(diff.Path, detail); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasChanges returns whether the report contains any differences
+func (r Report) HasChanges() bool {
+	return len(r.Diffs) > 0
+}
+
+// PathChanged returns whether the report contains a difference for exactly the
+// given path (in go-patch or dot-style) and the details of that difference.
+// A path that cannot be parsed is reported as unchanged.
+func (r Report) PathChanged(pathString string) (bool, []Detail) {
+	path, err := ytbx.ParsePathStringUnsafe(pathString)
+	if err != nil {
+		return false, nil
+	}
+
+	var details []Detail
+	for _, diff := range r.Diffs {
+		if diff.Path != nil && diff.Path.String() == path.String() {
+			details = append(details, diff.Details...)
+		}
+	}
+
+	return len(details) > 0, details
+}
+
+// ExitCode returns the program exit code for the report, which is zero if
+// there are no differences and the provided code if there are differences
+func (r Report) ExitCode(codeOnChanges int) int {
+	if r.HasChanges() {
+		return codeOnChanges
+	}
+
+	return 0
+}
+
+// GroupByKind returns the differences bucketed by the kinds of their details.
+// A difference with details of multiple kinds is part of each respective bucket.
+// Position changes of list entries are part of the order changes bucket.
+func (r Report) GroupByKind() (additions, removals, modifications, orderChanges []Diff) {
+	for _, diff := range r.Diffs {
+		kinds := map[rune]struct{}{}
+		for _, detail := range diff.Details {
+			kinds[detail.Kind] = struct{}{}
+		}
+
+		if _, ok := kinds[ADDITION]; ok {
+			additions = append(additions, diff)
+		}
+
+		if _, ok := kinds[REMOVAL]; ok {
+			removals = append(removals, diff)
+		}
+
+		if _, ok := kinds[MODIFICATION]; ok {
+			modifications = append(modifications, diff)
+		}
+
+		_, orderChange := kinds[ORDERCHANGE]
+		_, positionChange := kinds[POSITIONCHANGE]
+		if orderChange || positionChange {
+			orderChanges = append(orderChanges, diff)
+		}
+	}
+
+	return additions, removals, modifications, orderChanges
+}
+
+// Similarity returns a ratio between zero and one that describes how similar
+// the compared documents are based on their leaf nodes, i.e. scalars and empty
+// maps or lists. It is calculated as (Uf + Ut) / (Lf + Lt), where Lf and Lt are
+// the number of leaf nodes in the from and to documents, and Uf and Ut are the
+// number of those leaf nodes that are not part of any reported difference.
+// Order and position changes do not reduce the similarity. Identical documents have a
+// similarity of one, completely different documents a similarity of zero.
+func (r Report) Similarity() float64 {
+	return r.WeightedSimilarity(nil)
+}
+
+// WeightedSimilarity returns the similarity of the compared documents like
+// Similarity does, but each leaf node counts with the weight of its path. The
+// map keys are Go-Patch style path patterns, where `*` matches any single path
+// element, and the map values are the weights. Paths without a weight have a
+// weight of one.
+func (r Report) WeightedSimilarity(weights map[string]float64) float64 {
+	var totalFrom, totalTo float64
+	for idx, document := range r.From.Documents {
+		totalFrom += weighLeaves(ytbx.Path{DocumentIdx: idx}, document, weights)
+	}
+
+	for idx, document := range r.To.Documents {
+		totalTo += weighLeaves(ytbx.Path{DocumentIdx: idx}, document, weights)
+	}
+
+	if totalFrom+totalTo == 0 {
+		return 1.0
+	}
+
+	var changedFrom, changedTo float64
+	for _, diff := range r.Diffs {
+		var path ytbx.Path
+		if diff.Path != nil {
+			path = *diff.Path
+		}
+
+		for _, detail := range diff.Details {
+			if detail.Kind == ORDERCHANGE || detail.Kind == POSITIONCHANGE {
+				continue
+			}
+
+			changedFrom += weighLeaves(path, detail.From, weights)
+			changedTo += weighLeaves(path, detail.To, weights)
+		}
+	}
+
+	var unchanged float64
+	if changedFrom < totalFrom {
+		unchanged += totalFrom - changedFrom
+	}
+
+	if changedTo < totalTo {
+		unchanged += totalTo - changedTo
+	}
+
+	return unchanged / (totalFrom + totalTo)
+}
+
+// weighLeaves returns the sum of the weights of the leaf nodes (scalars,
+// aliases, and empty maps or lists) in the tree of the provided node, where
+// keys do not count
+func weighLeaves(path ytbx.Path, node *yamlv3.Node, weights map[string]float64) float64 {
+	if node == nil {
+		return 0
+	}
+
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		var result float64
+		for _, entry := range node.Content {
+			result += weighLeaves(path, entry, weights)
+		}
+
+		return result
+
+	case yamlv3.SequenceNode:
+		if len(node.Content) == 0 {
+			return fieldWeight(weights, path.String())
+		}
+
+		var result float64
+		for i, entry := range node.Content {
+			result += weighLeaves(ytbx.NewPathWithIndexedListElement(path, i), entry, weights)
+		}
+
+		return result
+
+	case yamlv3.MappingNode:
+		if len(node.Content) == 0 {
+			return fieldWeight(weights, path.String())
+		}
+
+		var result float64
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			result += weighLeaves(ytbx.NewPathWithNamedElement(path, node.Content[i].Value), node.Content[i+1], weights)
+		}
+
+		return result
+	}
+
+	return fieldWeight(weights, path.String())
+}
+
+// UnchangedPaths returns the paths of all leaf nodes (scalars and empty maps
+// or lists) of the from documents, which exist with the same value in the
+// respective to document and which are not part of any reported difference.
+// List entries are addressed by their index.
+func (r Report) UnchangedPaths() []*ytbx.Path {
+	changed := map[int][]string{}
+	markChanged := func(path ytbx.Path) {
+		if indexed, ok := r.indexedPath(path); ok {
+			changed[path.DocumentIdx] = append(changed[path.DocumentIdx], indexed)
+		}
+	}
+
+	for _, diff := range r.Diffs {
+		if diff.Path == nil {
+			continue
+		}
+
+		if len(diff.Details) == 0 {
+			markChanged(*diff.Path)
+		}
+
+		// added or removed map entries only change the respective keys, but
+		// not their siblings in the same map
+		for _, detail := range diff.Details {
+			var entries *yamlv3.Node
+			switch detail.Kind {
+			case ADDITION:
+				entries = followAlias(detail.To)
+
+			case REMOVAL:
+				entries = followAlias(detail.From)
+			}
+
+			if entries == nil || entries.Kind != yamlv3.MappingNode {
+				markChanged(*diff.Path)
+				continue
+			}
+
+			for i := 0; i < len(entries.Content); i += 2 {
+				markChanged(ytbx.NewPathWithNamedElement(*diff.Path, followAlias(entries.Content[i]).Value))
+			}
+		}
+	}
+
+	isChanged := func(path ytbx.Path) bool {
+		pathString := path.ToGoPatchStyle()
+		for _, changedPath := range changed[path.DocumentIdx] {
+			if changedPath == "/" || pathString == changedPath || strings.HasPrefix(pathString, changedPath+"/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var result []*ytbx.Path
+
+	var traverse func(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node)
+	traverse = func(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) {
+		from, to = followAlias(from), followAlias(to)
+		if to == nil || from.Kind != to.Kind || isChanged(path) {
+			return
+		}
+
+		switch {
+		case from.Kind == yamlv3.MappingNode && len(from.Content) > 0:
+			for i := 0; i+1 < len(from.Content); i += 2 {
+				key := from.Content[i].Value
+				if value, ok := findValueByKey(to, key); ok {
+					traverse(ytbx.NewPathWithNamedElement(path, key), from.Content[i+1], value)
+				}
+			}
+
+		case from.Kind == yamlv3.SequenceNode && len(from.Content) > 0:
+			for i, entry := range from.Content {
+				if i < len(to.Content) {
+					traverse(ytbx.NewPathWithIndexedListElement(path, i), entry, to.Content[i])
+				}
+			}
+
+		case len(from.Content) == 0 && len(to.Content) == 0 && from.ShortTag() == to.ShortTag() && from.Value == to.Value:
+			leaf := path
+			result = append(result, &leaf)
+		}
+	}
+
+	for idx, document := range r.From.Documents {
+		other := r.counterpartDocument(idx)
+		if document == nil || other == nil || isEmptyDocument(document) || isEmptyDocument(other) {
+			continue
+		}
+
+		traverse(ytbx.Path{Root: &r.From, DocumentIdx: idx}, document.Content[0], other.Content[0])
+	}
+
+	return result
+}
+
+// indexedPath returns the given path in Go-Patch style, where entries of
+// named-entry lists are addressed by their index in the from document, or
+// false if the path does not exist in the from document
+func (r Report) indexedPath(path ytbx.Path) (string, bool) {
+	if path.DocumentIdx < 0 || path.DocumentIdx >= len(r.From.Documents) || r.From.Documents[path.DocumentIdx] == nil {
+		return "", false
+	}
+
+	node := r.From.Documents[path.DocumentIdx]
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	result := ytbx.Path{DocumentIdx: path.DocumentIdx}
+	for _, element := range path.PathElements {
+		switch node = followAlias(node); {
+		case element.Key != "" && element.Name != "":
+			if node.Kind != yamlv3.SequenceNode {
+				return "", false
+			}
+
+			idx := -1
+			for i, entry := range node.Content {
+				if name, err := nameFromPath(followAlias(entry), ListItemIdentifierField(element.Key)); err == nil && name == element.Name {
+					idx = i
+					break
+				}
+			}
+
+			if idx < 0 {
+				return "", false
+			}
+
+			result = ytbx.NewPathWithIndexedListElement(result, idx)
+			node = node.Content[idx]
+
+		case element.Name != "":
+			if node.Kind != yamlv3.MappingNode {
+				return "", false
+			}
+
+			value, found := findValueByKey(node, element.Name)
+			if !found {
+				return "", false
+			}
+
+			result = ytbx.NewPathWithNamedElement(result, element.Name)
+			node = value
+
+		default:
+			if node.Kind != yamlv3.SequenceNode || element.Idx < 0 || element.Idx >= len(node.Content) {
+				return "", false
+			}
+
+			result = ytbx.NewPathWithIndexedListElement(result, element.Idx)
+			node = node.Content[element.Idx]
+		}
+	}
+
+	return result.ToGoPatchStyle(), true
+}
+
+// counterpartDocument returns the to document that was compared with the from
+// document at the given index, which is the one with the same name in case the
+// documents were matched by their names, or the one at the same index
+func (r Report) counterpartDocument(idx int) *yamlv3.Node {
+	if len(r.From.Names) == len(r.From.Documents) && len(r.To.Names) == len(r.To.Documents) && len(r.From.Names) > 0 {
+		for i, name := range r.To.Names {
+			if name == r.From.Names[idx] {
+				return r.To.Documents[i]
+			}
+		}
+
+		return nil
+	}
+
+	if idx < len(r.To.Documents) {
+		return r.To.Documents[idx]
+	}
+
+	return nil
+}
+
+// Moves returns the list entries of an order change, which moved to another
+// index, in the order of the `from` list. Equal entries are matched in the
+// order of their occurrence. For all other kinds of details, it returns nil.
+func (detail Detail) Moves() []Move {
+	if detail.Kind != ORDERCHANGE || detail.From == nil || detail.To == nil {
+		return nil
+	}
+
+	var cmpr compare
+	positions := map[uint64][]int{}
+	for idx, entry := range detail.To.Content {
+		hash := cmpr.calcNodeHash(entry)
+		positions[hash] = append(positions[hash], idx)
+	}
+
+	var moves []Move
+	for idx, entry := range detail.From.Content {
+		hash := cmpr.calcNodeHash(entry)
+		candidates := positions[hash]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		positions[hash] = candidates[1:]
+		if candidates[0] != idx {
+			moves = append(moves, Move{Value: entry, FromIndex: idx, ToIndex: candidates[0]})
+		}
+	}
+
+	return moves
+}
@@ -0,0 +1,650 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("Report", func() {
+	Context("partitioning a report", func() {
+		It("should split the differences into major and minor changes", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/description", dyff.MODIFICATION, "This is a long description text", "This is a long description test"),
+				singleDiff("/spec/image", dyff.MODIFICATION, "registry.example.org/foobar", "docker.io/library/barfoo"),
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("/spec/labels", dyff.ADDITION, nil, "foobar"),
+			}}
+
+			major, minor := report.Partition(0.1)
+			Expect(minor.Diffs).To(HaveLen(1))
+			Expect(minor.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+
+			Expect(major.Diffs).To(HaveLen(3))
+			Expect(major.Diffs[0]).To(BeSameDiffAs(report.Diffs[1]))
+			Expect(major.Diffs[1]).To(BeSameDiffAs(report.Diffs[2]))
+			Expect(major.Diffs[2]).To(BeSameDiffAs(report.Diffs[3]))
+		})
+
+		It("should consider a difference with mixed details as major", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				doubleDiff("/spec/list",
+					dyff.MODIFICATION, "foobar", "foobaz",
+					dyff.ORDERCHANGE, []string{"a", "b"}, []string{"b", "a"},
+				),
+			}}
+
+			major, minor := report.Partition(0.1)
+			Expect(major.Diffs).To(HaveLen(1))
+			Expect(minor.Diffs).To(BeEmpty())
+		})
+	})
+
+	Context("annotating a report", func() {
+		It("should attach notes to the differences with matching paths", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("/spec/image", dyff.MODIFICATION, "foo:1", "foo:2"),
+				singleDiff("/metadata/name", dyff.MODIFICATION, "foo", "bar"),
+			}}
+
+			result := report.Annotate(map[string]string{
+				"/spec/replicas":  "number of pods",
+				"metadata.name":   "name of the resource",
+				"/does/not/exist": "unused",
+			})
+
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0].Note).To(Equal("number of pods"))
+			Expect(result.Diffs[1].Note).To(BeEmpty())
+			Expect(result.Diffs[2].Note).To(Equal("name of the resource"))
+
+			// the original report must not be modified
+			Expect(report.Diffs[0].Note).To(BeEmpty())
+		})
+
+		It("should only attach notes with a document index to the differences in that document", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("#0/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("#1/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("#2/spec/replicas", dyff.MODIFICATION, 1, 2),
+			}}
+
+			result := report.Annotate(map[string]string{
+				"/spec/replicas":   "number of pods",
+				"#1/spec/replicas": "number of database pods",
+				"#2.spec.replicas": "number of cache pods",
+				"#3/spec/replicas": "unused",
+			})
+
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0].Note).To(Equal("number of pods"))
+			Expect(result.Diffs[1].Note).To(Equal("number of database pods"))
+			Expect(result.Diffs[2].Note).To(Equal("number of cache pods"))
+		})
+	})
+
+	Context("exit code semantics", func() {
+		It("should return zero for an empty report", func() {
+			report := dyff.Report{}
+			Expect(report.HasChanges()).To(BeFalse())
+			Expect(report.ExitCode(1)).To(Equal(0))
+		})
+
+		It("should return the configured code for a report with differences", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			}}
+
+			Expect(report.HasChanges()).To(BeTrue())
+			Expect(report.ExitCode(1)).To(Equal(1))
+			Expect(report.ExitCode(42)).To(Equal(42))
+		})
+	})
+
+	Context("grouping differences by kind", func() {
+		It("should bucket the differences by the kinds of their details", func() {
+			mixed := doubleDiff("/spec/list",
+				dyff.ORDERCHANGE, []string{"a", "b"}, []string{"b", "a"},
+				dyff.ADDITION, nil, []string{"c"},
+			)
+
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				mixed,
+				singleDiff("/spec/labels", dyff.REMOVAL, "foobar", nil),
+			}}
+
+			additions, removals, modifications, orderChanges := report.GroupByKind()
+			Expect(additions).To(HaveLen(1))
+			Expect(additions[0]).To(BeSameDiffAs(mixed))
+
+			Expect(removals).To(HaveLen(1))
+			Expect(removals[0]).To(BeSameDiffAs(report.Diffs[2]))
+
+			Expect(modifications).To(HaveLen(1))
+			Expect(modifications[0]).To(BeSameDiffAs(report.Diffs[0]))
+
+			Expect(orderChanges).To(HaveLen(1))
+			Expect(orderChanges[0]).To(BeSameDiffAs(mixed))
+		})
+	})
+
+	Context("calculating the similarity of documents", func() {
+		similarity := func(from, to string) float64 {
+			report, err := dyff.CompareBytes([]byte(from), []byte(to), dyff.Format(dyff.FormatYAML))
+			Expect(err).ToNot(HaveOccurred())
+			return report.Similarity()
+		}
+
+		It("should return one for identical documents", func() {
+			Expect(similarity(`{foo: bar, list: [1, 2], empty: {}}`, `{foo: bar, list: [1, 2], empty: {}}`)).To(Equal(1.0))
+		})
+
+		It("should return one half for documents where half of the leaves changed", func() {
+			Expect(similarity(`{foo: bar, bar: foo}`, `{foo: bar, bar: baz}`)).To(Equal(0.5))
+		})
+
+		It("should return zero for completely disjoint documents", func() {
+			Expect(similarity(`{foo: bar, list: [1, 2]}`, `{bar: foo, map: {key: value}}`)).To(Equal(0.0))
+		})
+
+		It("should not consider order changes", func() {
+			Expect(similarity(`{list: [1, 2, 3]}`, `{list: [3, 2, 1]}`)).To(Equal(1.0))
+		})
+
+		It("should weigh the leaf nodes with the given field weights", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`{name: foo, image: app:1.0, replicas: 1, port: 80}`)}
+			to := ytbx.InputFile{Documents: multiDoc(`{name: foo, image: app:2.0, replicas: 1, port: 80}`)}
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Similarity()).To(Equal(0.75))
+			Expect(report.WeightedSimilarity(map[string]float64{"/image": 5})).To(Equal(0.375))
+			Expect(report.WeightedSimilarity(map[string]float64{"/*": 2, "/image": 0})).To(Equal(1.0))
+		})
+	})
+
+	Context("mapping the details of a report", func() {
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			doubleDiff("/spec/list", dyff.REMOVAL, "foo", nil, dyff.ADDITION, nil, "bar"),
+			singleDiff("/metadata/name", dyff.REMOVAL, "foobar", nil),
+		}}
+
+		It("should keep all details if the function keeps them", func() {
+			result := report.MapDetails(func(_ dyff.Diff, detail dyff.Detail) (dyff.Detail, bool) {
+				return detail, true
+			})
+
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[1].Details).To(HaveLen(2))
+		})
+
+		It("should drop details and remove differences without remaining details", func() {
+			result := report.MapDetails(func(_ dyff.Diff, detail dyff.Detail) (dyff.Detail, bool) {
+				return detail, detail.Kind != dyff.REMOVAL
+			})
+
+			Expect(result.Diffs).To(HaveLen(2))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+			Expect(result.Diffs[1].Path.String()).To(Equal("/spec/list"))
+			Expect(result.Diffs[1].Details).To(HaveLen(1))
+			Expect(result.Diffs[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+		})
+
+		It("should rewrite details", func() {
+			result := report.MapDetails(func(diff dyff.Diff, detail dyff.Detail) (dyff.Detail, bool) {
+				if diff.Path.String() == "/spec/replicas" {
+					detail.To = nodify(3)
+				}
+
+				return detail, true
+			})
+
+			Expect(result.Diffs[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 3)))
+			Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2)))
+		})
+	})
+
+	Context("pruning a report", func() {
+		It("should remove parents that became empty after excluding their children", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/metadata", dyff.ADDITION, nil, yml(`{annotations: {foo: bar}}`)),
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				{Path: path("/spec/template")},
+			}}
+
+			// exclude the annotations from the added map entries
+			excluded := report.MapDetails(func(diff dyff.Diff, detail dyff.Detail) (dyff.Detail, bool) {
+				if diff.Path.String() == "/metadata" && detail.Kind == dyff.ADDITION {
+					detail.To = yml(`{}`)
+				}
+
+				return detail, true
+			})
+
+			Expect(excluded.Diffs).To(HaveLen(2))
+
+			result := excluded.Prune()
+			Expect(result.Diffs).To(HaveLen(1))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2)))
+		})
+
+		It("should keep additions and removals with content", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				doubleDiff("/spec/list", dyff.REMOVAL, yml(`[foo]`), nil, dyff.ADDITION, nil, yml(`[]`)),
+			}}
+
+			result := report.Prune()
+			Expect(result.Diffs).To(HaveLen(1))
+			Expect(result.Diffs[0].Details).To(HaveLen(1))
+			Expect(result.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+		})
+	})
+
+	Context("merging reports with their source", func() {
+		It("should tag each difference with the name of its originating report", func() {
+			result := dyff.MergeReportsWithSource(map[string]dyff.Report{
+				"service.yml": {Diffs: []dyff.Diff{
+					singleDiff("/spec/ports", dyff.MODIFICATION, 80, 8080),
+				}},
+				"deployment.yml": {Diffs: []dyff.Diff{
+					singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+					singleDiff("/metadata/name", dyff.MODIFICATION, "foo", "bar"),
+				}},
+				"configmap.yml": {},
+			})
+
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0].Source).To(Equal("deployment.yml"))
+			Expect(result.Diffs[0].Path.String()).To(Equal("/spec/replicas"))
+			Expect(result.Diffs[1].Source).To(Equal("deployment.yml"))
+			Expect(result.Diffs[1].Path.String()).To(Equal("/metadata/name"))
+			Expect(result.Diffs[2].Source).To(Equal("service.yml"))
+			Expect(result.Diffs[2].Path.String()).To(Equal("/spec/ports"))
+		})
+	})
+
+	Context("checking whether a specific path changed", func() {
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			singleDiff("/metadata/name", dyff.MODIFICATION, "foo", "bar"),
+		}}
+
+		It("should return the details of a changed path in go-patch or dot-style", func() {
+			changed, details := report.PathChanged("/spec/replicas")
+			Expect(changed).To(BeTrue())
+			Expect(details).To(HaveLen(1))
+			Expect(details[0].Kind).To(Equal(dyff.MODIFICATION))
+
+			changed, details = report.PathChanged("spec.replicas")
+			Expect(changed).To(BeTrue())
+			Expect(details).To(HaveLen(1))
+		})
+
+		It("should return false for an unchanged path", func() {
+			changed, details := report.PathChanged("/spec/template")
+			Expect(changed).To(BeFalse())
+			Expect(details).To(BeEmpty())
+
+			changed, _ = report.PathChanged("/spec")
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should return false for a malformed path", func() {
+			changed, details := report.PathChanged("spec..replicas")
+			Expect(changed).To(BeFalse())
+			Expect(details).To(BeEmpty())
+		})
+	})
+
+	Context("getting machine-readable change records", func() {
+		It("should return one record per detail with native values", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				doubleDiff("/spec/list", dyff.REMOVAL, yml(`[foo]`), nil, dyff.ADDITION, nil, yml(`[bar]`)),
+				singleDiff("/spec/order", dyff.ORDERCHANGE, yml(`[a, b]`), yml(`[b, a]`)),
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{app: foobar, enabled: true}`)),
+			}}
+
+			Expect(report.Records()).To(Equal([]dyff.ChangeRecord{
+				{Path: "/spec/replicas", Kind: "modification", From: 1, To: 2},
+				{Path: "/spec/list", Kind: "removal", From: []interface{}{"foo"}},
+				{Path: "/spec/list", Kind: "addition", To: []interface{}{"bar"}},
+				{Path: "/spec/order", Kind: "order-change", From: []interface{}{"a", "b"}, To: []interface{}{"b", "a"}},
+				{Path: "/metadata/labels", Kind: "addition", To: map[string]interface{}{"app": "foobar", "enabled": true}},
+			}))
+		})
+
+		It("should return one record per document of a multi-document addition", func() {
+			from := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+			)}
+
+			to := ytbx.InputFile{Documents: multiDoc(
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: b}}",
+				"{apiVersion: v1, kind: ConfigMap, metadata: {name: c}}",
+			)}
+
+			report, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Records()).To(Equal([]dyff.ChangeRecord{
+				{Path: "", Kind: "addition", To: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "b"}}},
+				{Path: "", Kind: "addition", To: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "c"}}},
+			}))
+		})
+
+		It("should return no records for a report without differences", func() {
+			Expect(dyff.Report{}.Records()).To(BeEmpty())
+		})
+	})
+
+	Context("getting the moves of an order change", func() {
+		moves := func(from, to string) []string {
+			detail := dyff.Detail{Kind: dyff.ORDERCHANGE, From: yml(from), To: yml(to)}
+
+			var result []string
+			for _, move := range detail.Moves() {
+				result = append(result, fmt.Sprintf("%s: %d → %d", move.Value.Value, move.FromIndex, move.ToIndex))
+			}
+
+			return result
+		}
+
+		It("should return the moves of a simple swap", func() {
+			Expect(moves(`[a, b, c]`, `[a, c, b]`)).To(Equal([]string{"b: 1 → 2", "c: 2 → 1"}))
+		})
+
+		It("should return the moves of a rotation", func() {
+			Expect(moves(`[a, b, c]`, `[b, c, a]`)).To(Equal([]string{"a: 0 → 2", "b: 1 → 0", "c: 2 → 1"}))
+		})
+
+		It("should return no moves for other kinds of details", func() {
+			detail := dyff.Detail{Kind: dyff.MODIFICATION, From: yml(`[a, b]`), To: yml(`[b, a]`)}
+			Expect(detail.Moves()).To(BeNil())
+		})
+	})
+
+	Context("iterating over the details", func() {
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			doubleDiff("/spec/list", dyff.REMOVAL, yml(`[foo]`), nil, dyff.ADDITION, nil, yml(`[bar]`)),
+			singleDiff("/metadata/name", dyff.MODIFICATION, "foo", "bar"),
+		}}
+
+		It("should visit the details in the order of the differences and their details", func() {
+			var visited []string
+			err := report.EachDetail(func(path *ytbx.Path, detail dyff.Detail) error {
+				visited = append(visited, fmt.Sprintf("%s %c", path.ToGoPatchStyle(), detail.Kind))
+				return nil
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(visited).To(Equal([]string{
+				"/spec/replicas ±",
+				"/spec/list -",
+				"/spec/list +",
+				"/metadata/name ±",
+			}))
+		})
+
+		It("should stop with the first error returned by the function", func() {
+			var count int
+			err := report.EachDetail(func(_ *ytbx.Path, detail dyff.Detail) error {
+				count++
+				if detail.Kind == dyff.REMOVAL {
+					return fmt.Errorf("stop")
+				}
+
+				return nil
+			})
+
+			Expect(err).To(MatchError("stop"))
+			Expect(count).To(Equal(2))
+		})
+	})
+
+	Context("getting the unchanged paths", func() {
+		It("should return the paths of all leaves that did not change", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`---
+name: foo
+replicas: 1
+labels: {app: foo, tier: web}
+ports: [80, 443]
+`)}
+
+			to := ytbx.InputFile{Documents: multiDoc(`---
+name: foo
+replicas: 2
+labels: {app: foo, tier: backend}
+ports: [80, 8443]
+`)}
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			var paths []string
+			for _, path := range report.UnchangedPaths() {
+				paths = append(paths, path.ToGoPatchStyle())
+			}
+
+			Expect(paths).To(Equal([]string{
+				"/name",
+				"/labels/app",
+			}))
+		})
+
+		It("should return all leaves if there are no differences", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`{foo: bar, list: [a, b], empty: {}}`)}
+
+			report, err := dyff.CompareInputFiles(from, from)
+			Expect(err).ToNot(HaveOccurred())
+
+			var paths []string
+			for _, path := range report.UnchangedPaths() {
+				paths = append(paths, path.ToGoPatchStyle())
+			}
+
+			Expect(paths).To(Equal([]string{"/foo", "/list/0", "/list/1", "/empty"}))
+		})
+
+		It("should return the siblings of added map entries", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`{a: {x: 1, y: 2}}`)}
+			to := ytbx.InputFile{Documents: multiDoc(`{a: {x: 1, y: 2, z: 3}}`)}
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			var paths []string
+			for _, path := range report.UnchangedPaths() {
+				paths = append(paths, path.ToGoPatchStyle())
+			}
+
+			Expect(paths).To(Equal([]string{"/a/x", "/a/y"}))
+		})
+
+		It("should match differences in named-entry lists with the list entry indices", func() {
+			input := ytbx.InputFile{Documents: multiDoc(`{list: [{name: one, value: 1}, {name: two, value: 2}]}`)}
+			report := dyff.Report{
+				From:  input,
+				To:    input,
+				Diffs: []dyff.Diff{singleDiff("/list/name=two/value", dyff.MODIFICATION, 2, 3)},
+			}
+
+			var paths []string
+			for _, path := range report.UnchangedPaths() {
+				paths = append(paths, path.ToGoPatchStyle())
+			}
+
+			Expect(paths).To(Equal([]string{"/list/0/name", "/list/0/value", "/list/1/name"}))
+		})
+	})
+
+	Context("removing duplicate subtree additions and removals", func() {
+		It("should drop additions below an added map entry", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec", dyff.ADDITION, nil, yml(`{template: {labels: {app: foo}}}`)),
+				singleDiff("/spec/template", dyff.ADDITION, nil, yml(`{labels: {app: foo}}`)),
+				singleDiff("/spec/template/labels/app", dyff.MODIFICATION, nil, "foo"),
+				singleDiff("/metadata/labels", dyff.ADDITION, nil, yml(`{app: foo}`)),
+			}}
+
+			result := report.DedupeSubtrees()
+			Expect(result.Diffs).To(HaveLen(2))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+			Expect(result.Diffs[1]).To(BeSameDiffAs(report.Diffs[3]))
+		})
+
+		It("should drop removals below a removed path, but keep other kinds of details", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/template", dyff.MODIFICATION, yml(`{labels: {app: foo}}`), nil),
+				singleDiff("/spec/template/labels", dyff.REMOVAL, yml(`{app: foo}`), nil),
+				singleDiff("/spec/template/labels/tier", dyff.ADDITION, nil, yml(`{name: web}`)),
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			}}
+
+			result := report.DedupeSubtrees()
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+			Expect(result.Diffs[1]).To(BeSameDiffAs(report.Diffs[2]))
+			Expect(result.Diffs[2]).To(BeSameDiffAs(report.Diffs[3]))
+		})
+
+		It("should keep additions of other sources in a merged report", func() {
+			report := dyff.MergeReportsWithSource(map[string]dyff.Report{
+				"a.yml": {Diffs: []dyff.Diff{singleDiff("/spec", dyff.ADDITION, nil, yml(`{foo: {bar: baz}}`))}},
+				"b.yml": {Diffs: []dyff.Diff{
+					singleDiff("/spec", dyff.ADDITION, nil, yml(`{foo: {bar: baz}}`)),
+					singleDiff("/spec/foo", dyff.ADDITION, nil, yml(`{qux: quux}`)),
+				}},
+				"c.yml": {Diffs: []dyff.Diff{singleDiff("/spec/foo", dyff.ADDITION, nil, yml(`{qux: quux}`))}},
+			})
+
+			result := report.DedupeSubtrees()
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0].Source).To(Equal("a.yml"))
+			Expect(result.Diffs[1].Source).To(Equal("b.yml"))
+			Expect(result.Diffs[1].Path.String()).To(Equal("/spec"))
+			Expect(result.Diffs[2].Source).To(Equal("c.yml"))
+			Expect(result.Diffs[2].Path.String()).To(Equal("/spec/foo"))
+		})
+	})
+
+	Context("filtering by values", func() {
+		report := dyff.Report{Diffs: []dyff.Diff{
+			singleDiff("/spec/image", dyff.MODIFICATION, "app:1.0", "app:latest"),
+			singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+			doubleDiff("/spec/sidecars", dyff.REMOVAL, list(`[proxy:1.0]`), nil, dyff.ADDITION, nil, list(`[proxy:latest]`)),
+			singleDiff("/metadata/labels", dyff.REMOVAL, yml(`{tag: latest}`), nil),
+		}}
+
+		containsLatest := func(_ *yamlv3.Node, to *yamlv3.Node) bool {
+			if to == nil {
+				return false
+			}
+
+			out, err := yamlv3.Marshal(to)
+			return err == nil && strings.Contains(string(out), "latest")
+		}
+
+		It("should keep the differences with a new value that satisfies the predicate", func() {
+			result := report.FilterByValue(containsLatest)
+			Expect(result.Diffs).To(HaveLen(2))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+			Expect(result.Diffs[1]).To(BeSameDiffAs(report.Diffs[2]))
+		})
+
+		It("should return an empty report if no values satisfy the predicate", func() {
+			result := report.FilterByValue(func(_, _ *yamlv3.Node) bool { return false })
+			Expect(result.Diffs).To(BeEmpty())
+		})
+	})
+
+	Context("filtering security-relevant changes", func() {
+		It("should keep changes of security-relevant fields and drop unrelated ones", func() {
+			report := dyff.Report{Diffs: []dyff.Diff{
+				singleDiff("/spec/template/spec/containers/name=app/securityContext/privileged", dyff.MODIFICATION, false, true),
+				singleDiff("/metadata/labels/app", dyff.MODIFICATION, "foo", "bar"),
+				singleDiff("/spec/template/spec/hostNetwork", dyff.MODIFICATION, false, true),
+				singleDiff("/spec/replicas", dyff.MODIFICATION, 1, 2),
+				singleDiff("/rules/0/verbs", dyff.ADDITION, nil, list(`[delete]`)),
+			}}
+
+			result := report.FilterRegexp(dyff.SecurityDefaults()...)
+			Expect(result.Diffs).To(HaveLen(3))
+			Expect(result.Diffs[0]).To(BeSameDiffAs(report.Diffs[0]))
+			Expect(result.Diffs[1]).To(BeSameDiffAs(report.Diffs[2]))
+			Expect(result.Diffs[2]).To(BeSameDiffAs(report.Diffs[4]))
+		})
+
+		It("should keep added security-relevant fields that are reported at a parent path", func() {
+			from := []byte(`---
+kind: Pod
+spec:
+  containers:
+  - name: app
+    image: app:1.0
+`)
+
+			to := []byte(`---
+kind: Pod
+spec:
+  hostNetwork: true
+  containers:
+  - name: app
+    image: app:1.1
+  - name: sidecar
+    image: sidecar:1.0
+    securityContext:
+      privileged: true
+`)
+
+			report, err := dyff.CompareBytes(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			result := report.FilterRegexpSubtrees(dyff.SecurityDefaults()...)
+			Expect(result.Diffs).To(HaveLen(2))
+
+			Expect(result.Diffs[0].Path.String()).To(Equal("/spec"))
+			Expect(result.Diffs[0].Details).To(HaveLen(1))
+			Expect(result.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+			Expect(result.Diffs[0].Details[0].To.Content).To(HaveLen(2))
+			Expect(result.Diffs[0].Details[0].To.Content[0].Value).To(Equal("hostNetwork"))
+
+			Expect(result.Diffs[1].Path.String()).To(Equal("/spec/containers"))
+			Expect(result.Diffs[1].Details).To(HaveLen(1))
+			Expect(result.Diffs[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+			Expect(result.Diffs[1].Details[0].To.Content).To(HaveLen(1))
+			Expect(result.Diffs[1].Details[0].To.Content[0].Content[1].Value).To(Equal("sidecar"))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// CompareStreams compares two streams of YAML documents one document pair at a
+// time, where documents are paired by their position in the streams. Only the
+// current pair of documents is kept in memory, the differences of each pair are
+// passed to the callback before the next pair is read. Documents that only
+// exist in one of the streams are reported as whole document additions or
+// removals. Options that require the complete report (KeysOnly, KindAllowlist,
+// DetectDuplicateKeys), the Kubernetes document look-up by name, and options
+// working on the input files (DocumentSelector, RootPath, PreprocessFunc) are
+// not supported. A callback error stops the comparison and is returned.
+func CompareStreams(from io.Reader, to io.Reader, callback func(Diff) error, compareOptions ...CompareOption) error {
+	cmpr := newCompare(compareOptions...)
+	fromDecoder, toDecoder := yamlv3.NewDecoder(from), yamlv3.NewDecoder(to)
+
+	for idx := 0; ; idx++ {
+		fromDocument, err := nextDocument(fromDecoder)
+		if err != nil {
+			return fmt.Errorf("failed to read document #%d of from stream: %w", idx+1, err)
+		}
+
+		toDocument, err := nextDocument(toDecoder)
+		if err != nil {
+			return fmt.Errorf("failed to read document #%d of to stream: %w", idx+1, err)
+		}
+
+		var diffs []Diff
+		switch {
+		case fromDocument == nil && toDocument == nil:
+			return nil
+
+		case fromDocument == nil:
+			if !cmpr.isSuppressed(ADDITION) {
+				diffs = []Diff{{Details: []Detail{{Kind: ADDITION, From: nil, To: documentsNode(ytbx.InputFile{Documents: []*yamlv3.Node{toDocument}})}}}}
+			}
+
+		case toDocument == nil:
+			if !cmpr.isSuppressed(REMOVAL) {
+				diffs = []Diff{{Details: []Detail{{Kind: REMOVAL, From: documentsNode(ytbx.InputFile{Documents: []*yamlv3.Node{fromDocument}}), To: nil}}}}
+			}
+
+		default:
+			if diffs, err = cmpr.objects(ytbx.Path{DocumentIdx: idx}, fromDocument, toDocument); err != nil {
+				return err
+			}
+		}
+
+		for _, diff := range diffs {
+			if err := callback(diff); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// nextDocument returns the next document of the decoder, or nil at the end of
+// the stream
+func nextDocument(decoder *yamlv3.Decoder) (*yamlv3.Node, error) {
+	var document yamlv3.Node
+	if err := decoder.Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &document, nil
+}
@@ -0,0 +1,110 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("comparing streams", func() {
+	Context("comparing two streams of documents", func() {
+		It("should report the differences of each document pair", func() {
+			from := strings.NewReader("---\nname: foo\n---\nname: bar\n---\nname: removed\n")
+			to := strings.NewReader("---\nname: foo\n---\nname: baz\n")
+
+			var diffs []dyff.Diff
+			Expect(dyff.CompareStreams(from, to, func(diff dyff.Diff) error {
+				diffs = append(diffs, diff)
+				return nil
+			})).To(Succeed())
+
+			Expect(diffs).To(HaveLen(2))
+			Expect(diffs[0].Path.DocumentIdx).To(Equal(1))
+			Expect(diffs[0].Path.String()).To(Equal("/name"))
+			Expect(diffs[0].Details[0].Kind).To(Equal(dyff.MODIFICATION))
+			Expect(diffs[1].Path).To(BeNil())
+			Expect(diffs[1].Details[0].Kind).To(Equal(dyff.REMOVAL))
+		})
+
+		It("should only keep a few documents in memory for a large stream", func() {
+			const count = 20000
+
+			// generate the streams lazily and keep track of how many documents were
+			// produced, so that it is possible to verify that the comparison does
+			// not read ahead the whole stream
+			var produced int64
+			stream := func(value func(int) string) io.Reader {
+				reader, writer := io.Pipe()
+				go func() {
+					for i := 0; i < count; i++ {
+						atomic.AddInt64(&produced, 1)
+						if _, err := fmt.Fprintf(writer, "---\nindex: %d\nvalue: %s\n", i, value(i)); err != nil {
+							return
+						}
+					}
+
+					writer.Close()
+				}()
+
+				return reader
+			}
+
+			from := stream(func(int) string { return "foo" })
+			to := stream(func(i int) string {
+				if i%1000 == 0 {
+					return "bar"
+				}
+
+				return "foo"
+			})
+
+			var diffs int
+			var maxReadAhead int64
+			Expect(dyff.CompareStreams(from, to, func(diff dyff.Diff) error {
+				if readAhead := atomic.LoadInt64(&produced) - 2*int64(diff.Path.DocumentIdx); readAhead > maxReadAhead {
+					maxReadAhead = readAhead
+				}
+
+				diffs++
+				return nil
+			})).To(Succeed())
+
+			Expect(diffs).To(Equal(count / 1000))
+			Expect(maxReadAhead).To(BeNumerically("<", 2000))
+		})
+
+		It("should stop with the error of the callback", func() {
+			err := dyff.CompareStreams(strings.NewReader("foo: bar\n"), strings.NewReader("foo: baz\n"), func(dyff.Diff) error {
+				return fmt.Errorf("stop")
+			})
+
+			Expect(err).To(MatchError("stop"))
+		})
+	})
+})
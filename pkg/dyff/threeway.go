@@ -0,0 +1,296 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Sources of the differences in a three-way comparison report
+const (
+	SourceOurs           = "ours"
+	SourceTheirs         = "theirs"
+	SourceBoth           = "both"
+	SourceConflictOurs   = "ours (conflict)"
+	SourceConflictTheirs = "theirs (conflict)"
+)
+
+// ThreeWayCompare compares both the ours and theirs input files with their
+// common base and reports which side changed a path. Each difference has its
+// source set to the side it originates from: `ours` or `theirs` if only one
+// side changed the path, and `both` if both sides made the same change. If
+// both sides changed the same path differently, the conflict is reported as
+// two consecutive differences with the sources `ours (conflict)` and
+// `theirs (conflict)`. Changes at a path and inside of it, for example the
+// removal of a map entry on one side and the modification of a value inside of
+// that entry on the other side, are conflicting as well, whereas both sides can
+// add or remove different entries of the same map, or different Kubernetes
+// documents, without a conflict. The report uses the base as from and ours as
+// to input.
+func ThreeWayCompare(base ytbx.InputFile, ours ytbx.InputFile, theirs ytbx.InputFile, compareOptions ...CompareOption) (Report, error) {
+	cmpr := newCompare(compareOptions...)
+
+	oursReport, err := CompareInputFiles(base, ours, compareOptions...)
+	if err != nil {
+		return Report{}, err
+	}
+
+	theirsReport, err := CompareInputFiles(base, theirs, compareOptions...)
+	if err != nil {
+		return Report{}, err
+	}
+
+	oursUnits, theirsUnits := threeWayUnits(oursReport.Diffs), threeWayUnits(theirsReport.Diffs)
+
+	result := Report{
+		From:     oursReport.From,
+		To:       oursReport.To,
+		Warnings: append(oursReport.Warnings, theirsReport.Warnings...),
+	}
+
+	var diffs []Diff
+	handled := make([]bool, len(theirsUnits))
+	for _, unit := range oursUnits {
+		var overlapping []int
+		for i, other := range theirsUnits {
+			if isOverlappingKey(unit.key, other.key) {
+				overlapping = append(overlapping, i)
+			}
+		}
+
+		switch {
+		case len(overlapping) == 0:
+			unit.diff.Source = SourceOurs
+			diffs = append(diffs, unit.diff)
+
+		case len(overlapping) == 1 && unit.key == theirsUnits[overlapping[0]].key && cmpr.isSameChange(unit.diff.Details, theirsUnits[overlapping[0]].diff.Details):
+			handled[overlapping[0]] = true
+			unit.diff.Source = SourceBoth
+			diffs = append(diffs, unit.diff)
+
+		default:
+			unit.diff.Source = SourceConflictOurs
+			diffs = append(diffs, unit.diff)
+			for _, i := range overlapping {
+				if !handled[i] {
+					handled[i] = true
+					other := theirsUnits[i].diff
+					other.Source = SourceConflictTheirs
+					diffs = append(diffs, other)
+				}
+			}
+		}
+	}
+
+	for i, unit := range theirsUnits {
+		if !handled[i] {
+			unit.diff.Source = SourceTheirs
+			diffs = append(diffs, unit.diff)
+		}
+	}
+
+	// join consecutive differences at the same path from the same source,
+	// which were split up into units before
+	for _, diff := range diffs {
+		if n := len(result.Diffs); n > 0 && result.Diffs[n-1].Source == diff.Source && threeWayKey(result.Diffs[n-1]) == threeWayKey(diff) {
+			result.Diffs[n-1].Details = append(result.Diffs[n-1].Details, diff.Details...)
+			continue
+		}
+
+		result.Diffs = append(result.Diffs, diff)
+	}
+
+	return result, nil
+}
+
+// threeWayUnit is a part of a difference, that is changed as a whole, with the
+// document index and path of what it changes as its key
+type threeWayUnit struct {
+	key  string
+	diff Diff
+}
+
+// threeWayUnits splits the differences into units, where the addition or
+// removal of map entries is split up per map key, and the addition or removal
+// of whole documents is split up per document, so that both sides can add or
+// remove different keys of the same map, or different documents, without a
+// conflict
+func threeWayUnits(diffs []Diff) []threeWayUnit {
+	var units []threeWayUnit
+	for _, diff := range diffs {
+		if diff.Path == nil {
+			units = append(units, documentUnits(diff)...)
+			continue
+		}
+
+		var rest []Detail
+		var entries []threeWayUnit
+		for _, detail := range diff.Details {
+			node := detail.To
+			if detail.Kind == REMOVAL {
+				node = detail.From
+			}
+
+			if (detail.Kind != ADDITION && detail.Kind != REMOVAL) || node == nil || node.Kind != yamlv3.MappingNode {
+				rest = append(rest, detail)
+				continue
+			}
+
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				entry := *node
+				entry.Content = []*yamlv3.Node{node.Content[i], node.Content[i+1]}
+
+				split := Detail{Kind: detail.Kind}
+				if detail.Kind == ADDITION {
+					split.To = &entry
+				} else {
+					split.From = &entry
+				}
+
+				entries = append(entries, threeWayUnit{
+					key:  documentPathKey(ytbx.NewPathWithNamedElement(*diff.Path, node.Content[i].Value)),
+					diff: Diff{Path: diff.Path, Details: []Detail{split}},
+				})
+			}
+		}
+
+		if len(rest) > 0 {
+			units = append(units, threeWayUnit{key: threeWayKey(diff), diff: Diff{Path: diff.Path, Details: rest}})
+		}
+
+		units = append(units, entries...)
+	}
+
+	return units
+}
+
+// documentUnits splits the addition or removal of whole documents into one
+// unit per document, which uses the name of the document as its key
+func documentUnits(diff Diff) []threeWayUnit {
+	var rest []Detail
+	var documents []threeWayUnit
+	for _, detail := range diff.Details {
+		node := detail.To
+		if detail.Kind == REMOVAL {
+			node = detail.From
+		}
+
+		if (detail.Kind != ADDITION && detail.Kind != REMOVAL) || node == nil || node.Kind != yamlv3.DocumentNode {
+			rest = append(rest, detail)
+			continue
+		}
+
+		for _, document := range node.Content {
+			name, err := fqrn(document)
+			if err != nil {
+				return []threeWayUnit{{key: threeWayKey(diff), diff: diff}}
+			}
+
+			split := Detail{Kind: detail.Kind}
+			wrapper := &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{document}}
+			if detail.Kind == ADDITION {
+				split.To = wrapper
+			} else {
+				split.From = wrapper
+			}
+
+			documents = append(documents, threeWayUnit{
+				key:  "document:" + name,
+				diff: Diff{Details: []Detail{split}},
+			})
+		}
+	}
+
+	var units []threeWayUnit
+	if len(rest) > 0 {
+		units = append(units, threeWayUnit{key: threeWayKey(diff), diff: Diff{Details: rest}})
+	}
+
+	return append(units, documents...)
+}
+
+// threeWayKey returns the document index and path of the difference as a key
+// to look up the difference at the same path in the other report
+func threeWayKey(diff Diff) string {
+	if diff.Path == nil {
+		return ""
+	}
+
+	return documentPathKey(*diff.Path)
+}
+
+// isOverlappingKey returns whether both keys refer to the same path, or one
+// path is inside of the other one, so that changes at both paths conflict
+func isOverlappingKey(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	if a == "" || b == "" {
+		return false
+	}
+
+	isInside := func(path string, parent string) bool {
+		return strings.HasPrefix(path, strings.TrimSuffix(parent, "/")+"/")
+	}
+
+	return isInside(a, b) || isInside(b, a)
+}
+
+// isSameChange returns whether both lists of details describe the same change
+func (compare *compare) isSameChange(a []Detail, b []Detail) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Kind != b[i].Kind || !compare.isSameNode(a[i].From, b[i].From) || !compare.isSameNode(a[i].To, b[i].To) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (compare *compare) isSameNode(a *yamlv3.Node, b *yamlv3.Node) bool {
+	switch {
+	case a == nil || b == nil:
+		return a == b
+
+	case a.Kind == yamlv3.DocumentNode || b.Kind == yamlv3.DocumentNode:
+		if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+			return false
+		}
+
+		for i := range a.Content {
+			if !compare.isSameNode(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a.Kind == b.Kind && compare.calcNodeHash(a) == compare.calcNodeHash(b)
+}
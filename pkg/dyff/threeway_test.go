@@ -0,0 +1,148 @@
+// Copyright © 2019 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gonvenience/ytbx"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("Three-way comparison", func() {
+	base := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 1, image: app:1.0, port: 80}`)}
+
+	It("should report a clean change on only one side", func() {
+		ours := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 2, image: app:1.0, port: 80}`)}
+		theirs := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 1, image: app:1.0, port: 8080}`)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, theirs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(2))
+
+		Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/replicas", dyff.MODIFICATION, 1, 2)))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceOurs))
+
+		Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/port", dyff.MODIFICATION, 80, 8080)))
+		Expect(report.Diffs[1].Source).To(Equal(dyff.SourceTheirs))
+	})
+
+	It("should report the same change on both sides once", func() {
+		ours := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 2, image: app:1.0, port: 80}`)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, ours)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(1))
+		Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/replicas", dyff.MODIFICATION, 1, 2)))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceBoth))
+	})
+
+	It("should report a conflicting change on both sides with the values of each side", func() {
+		ours := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 1, image: app:2.0, port: 80}`)}
+		theirs := ytbx.InputFile{Documents: multiDoc(`{name: foo, replicas: 1, image: app:3.0, port: 80}`)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, theirs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(2))
+
+		Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/image", dyff.MODIFICATION, "app:1.0", "app:2.0")))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceConflictOurs))
+
+		Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/image", dyff.MODIFICATION, "app:1.0", "app:3.0")))
+		Expect(report.Diffs[1].Source).To(Equal(dyff.SourceConflictTheirs))
+	})
+
+	It("should report additions of different keys of the same map on both sides as clean changes", func() {
+		base := ytbx.InputFile{Documents: multiDoc(`{spec: {a: 1}}`)}
+		ours := ytbx.InputFile{Documents: multiDoc(`{spec: {a: 1, x: 2}}`)}
+		theirs := ytbx.InputFile{Documents: multiDoc(`{spec: {a: 1, y: 3}}`)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, theirs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(2))
+
+		Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/spec", dyff.ADDITION, nil, yml(`{x: 2}`))))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceOurs))
+
+		Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/spec", dyff.ADDITION, nil, yml(`{y: 3}`))))
+		Expect(report.Diffs[1].Source).To(Equal(dyff.SourceTheirs))
+	})
+
+	It("should report the removal of a map entry on one side and a change inside of it on the other side as a conflict", func() {
+		base := ytbx.InputFile{Documents: multiDoc(`{spec: {a: {b: 1}, c: 1}}`)}
+		ours := ytbx.InputFile{Documents: multiDoc(`{spec: {c: 1}}`)}
+		theirs := ytbx.InputFile{Documents: multiDoc(`{spec: {a: {b: 2}, c: 1}}`)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, theirs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(2))
+
+		Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/spec", dyff.REMOVAL, yml(`{a: {b: 1}}`), nil)))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceConflictOurs))
+
+		Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/spec/a/b", dyff.MODIFICATION, 1, 2)))
+		Expect(report.Diffs[1].Source).To(Equal(dyff.SourceConflictTheirs))
+	})
+
+	It("should report additions of different documents on both sides as clean changes", func() {
+		base := ytbx.InputFile{Documents: multiDoc("{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}")}
+		ours := ytbx.InputFile{Documents: multiDoc(
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: b}}",
+		)}
+
+		theirs := ytbx.InputFile{Documents: multiDoc(
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: c}}",
+		)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, theirs, dyff.KubernetesEntityDetection(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(2))
+
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceOurs))
+		Expect(report.Diffs[0].Details).To(HaveLen(1))
+		Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+		Expect(report.Diffs[0].Details[0].To.Content).To(HaveLen(1))
+		Expect(report.Diffs[0].Details[0].To.Content[0]).To(Equal(ours.Documents[1].Content[0]))
+
+		Expect(report.Diffs[1].Source).To(Equal(dyff.SourceTheirs))
+		Expect(report.Diffs[1].Details).To(HaveLen(1))
+		Expect(report.Diffs[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+		Expect(report.Diffs[1].Details[0].To.Content).To(HaveLen(1))
+		Expect(report.Diffs[1].Details[0].To.Content[0]).To(Equal(theirs.Documents[1].Content[0]))
+	})
+
+	It("should report the addition of the same document on both sides once", func() {
+		base := ytbx.InputFile{Documents: multiDoc("{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}")}
+		ours := ytbx.InputFile{Documents: multiDoc(
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: a}}",
+			"{apiVersion: v1, kind: ConfigMap, metadata: {name: b}}",
+		)}
+
+		report, err := dyff.ThreeWayCompare(base, ours, ours, dyff.KubernetesEntityDetection(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).To(HaveLen(1))
+		Expect(report.Diffs[0].Source).To(Equal(dyff.SourceBoth))
+	})
+})